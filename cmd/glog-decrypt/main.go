@@ -0,0 +1,81 @@
+// Command glog-decrypt decrypts log files written through glog.EncryptWriter.
+//
+//	glog-decrypt -key 2024-01=<hex key> -key 2024-02=<hex key> app.log.enc
+//
+// Without file arguments frames are read from stdin.
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+type keyFlag map[string][]byte
+
+func (k keyFlag) String() string {
+	ids := make([]string, 0, len(k))
+	for id := range k {
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func (k keyFlag) Set(v string) error {
+	id, hexKey, ok := strings.Cut(v, "=")
+	if !ok || id == "" {
+		return fmt.Errorf("expected id=hexkey, got %q", v)
+	}
+
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return fmt.Errorf("key %s: %w", id, err)
+	}
+
+	k[id] = key
+	return nil
+}
+
+func main() {
+	keys := keyFlag{}
+	flag.Var(keys, "key", "decryption key as id=hexkey, may be repeated")
+	flag.Parse()
+
+	if len(keys) == 0 {
+		fmt.Fprintln(os.Stderr, "glog-decrypt: at least one -key is required")
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 0 {
+		if err := glog.DecryptLog(os.Stdout, os.Stdin, keys); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	for _, name := range flag.Args() {
+		if err := decryptFile(name, os.Stdout, keys); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func decryptFile(name string, dst io.Writer, keys map[string][]byte) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return glog.DecryptLog(dst, f, keys)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "glog-decrypt:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+type filter struct {
+	level   slog.Level
+	loggers map[string]bool
+	since   time.Time
+	where   map[string]string
+}
+
+func newFilter(level, loggers, since string, where []string) (*filter, error) {
+	f := &filter{
+		loggers: map[string]bool{},
+		where:   map[string]string{},
+	}
+
+	var err error
+	if f.level, err = glog.ParseLevel(level); err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(loggers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			f.loggers[name] = true
+		}
+	}
+
+	if since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			f.since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			f.since = t
+		} else {
+			return nil, fmt.Errorf("invalid --since %q", since)
+		}
+	}
+
+	for _, w := range where {
+		key, val, _ := strings.Cut(w, "=")
+		f.where[key] = val
+	}
+
+	return f, nil
+}
+
+func (f *filter) match(r *record) bool {
+	if r.level < f.level {
+		return false
+	}
+
+	if len(f.loggers) > 0 && !f.loggers[r.logger] {
+		return false
+	}
+
+	if !f.since.IsZero() && r.time.Before(f.since) {
+		return false
+	}
+
+	for key, want := range f.where {
+		v, ok := r.lookup(key)
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,120 @@
+// Command glog pretty prints JSON lines logs written by glog.
+//
+//	glog [flags] [file ...]
+//
+// Records are read from the given files, or stdin when none are given,
+// and rendered with glog's ColorConsoleHandler. Lines that are not JSON
+// objects are printed unchanged.
+//
+//	tail -f app.log | glog --level warn --logger db --where tenant=acme
+//
+// With --tui an interactive viewer follows the input. Keys 1-6 toggle
+// levels, / sets a text filter, : adds a key=value filter, c clears
+// filters, enter expands the selected record, f toggles follow mode and
+// q quits.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+type whereFlag []string
+
+func (w *whereFlag) String() string { return strings.Join(*w, ",") }
+
+func (w *whereFlag) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expected key=value, got %q", v)
+	}
+	*w = append(*w, v)
+	return nil
+}
+
+func main() {
+	var (
+		level     = flag.String("level", "trace", "minimum level to show")
+		loggers   = flag.String("logger", "", "comma separated logger names to show")
+		since     = flag.String("since", "", "only show records newer than a duration (10m) or RFC3339 time")
+		colorMode = flag.String("color", "auto", "color output: auto, always or never")
+		multiline = flag.Bool("multiline", false, "render nested values on continuation lines")
+		tuiMode   = flag.Bool("tui", false, "interactive viewer that follows the input")
+		where     whereFlag
+	)
+	flag.Var(&where, "where", "only show records where key equals value, may be repeated")
+	flag.Parse()
+
+	f, err := newFilter(*level, *loggers, *since, where)
+	if err != nil {
+		fail(err)
+	}
+
+	mode := glog.ColorAuto
+	switch *colorMode {
+	case "always":
+		mode = glog.ColorForceOn
+	case "never":
+		mode = glog.ColorForceOff
+	}
+
+	if *tuiMode {
+		if err := runTUI(f, mode, flag.Args()); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	handler := glog.NewColorConsoleHandler(os.Stdout,
+		&slog.HandlerOptions{Level: glog.LevelTrace, AddSource: true},
+		glog.WithColorConsoleColorMode(mode),
+		glog.WithColorConsoleMultiline(*multiline),
+	)
+
+	p := &printer{handler: handler, filter: f, out: os.Stdout}
+
+	if flag.NArg() == 0 {
+		if err := p.run(os.Stdin); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	for _, name := range flag.Args() {
+		if err := p.runFile(name); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func (p *printer) runFile(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return p.run(file)
+}
+
+func (p *printer) run(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		p.line(scanner.Bytes())
+	}
+
+	return scanner.Err()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "glog:", err)
+	os.Exit(1)
+}
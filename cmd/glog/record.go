@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+type printer struct {
+	handler slog.Handler
+	filter  *filter
+	out     io.Writer
+}
+
+// record is a decoded JSON line
+type record struct {
+	time    time.Time
+	level   slog.Level
+	msg     string
+	logger  string
+	source  string
+	attrs   map[string]any
+	rawKeys []string
+	raw     string
+}
+
+func (p *printer) line(line []byte) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		fmt.Fprintf(p.out, "%s\n", line)
+		return
+	}
+
+	rec, err := parseRecord(trimmed)
+	if err != nil {
+		fmt.Fprintf(p.out, "%s\n", line)
+		return
+	}
+
+	if !p.filter.match(rec) {
+		return
+	}
+
+	_ = p.handler.Handle(context.Background(), rec.slogRecord())
+}
+
+func parseRecord(line []byte) (*record, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	rec := &record{level: slog.LevelInfo, attrs: fields, raw: string(line)}
+
+	for _, key := range []string{glog.TimeKey, slog.TimeKey} {
+		if v, ok := fields[key]; ok {
+			rec.time = parseTime(v)
+			delete(fields, key)
+			break
+		}
+	}
+
+	if v, ok := fields[glog.LevelKey].(string); ok {
+		rec.level, _ = glog.ParseLevel(v)
+		delete(fields, glog.LevelKey)
+	}
+
+	if v, ok := fields[glog.MessageKey].(string); ok {
+		rec.msg = v
+		delete(fields, glog.MessageKey)
+	}
+
+	if v, ok := fields[glog.LoggerKey].(string); ok {
+		rec.logger = v
+	}
+
+	if v, ok := fields[slog.SourceKey]; ok {
+		rec.source = formatSource(v)
+		delete(fields, slog.SourceKey)
+	}
+
+	for key := range fields {
+		rec.rawKeys = append(rec.rawKeys, key)
+	}
+	sort.Strings(rec.rawKeys)
+
+	return rec, nil
+}
+
+func (r *record) slogRecord() slog.Record {
+	sr := slog.NewRecord(r.time, r.level, r.msg, 0)
+	for _, key := range r.rawKeys {
+		sr.AddAttrs(slog.Any(key, r.attrs[key]))
+	}
+	if r.source != "" {
+		sr.AddAttrs(slog.String(slog.SourceKey, r.source))
+	}
+	return sr
+}
+
+// lookup resolves a dotted key, descending into nested objects
+func (r *record) lookup(key string) (any, bool) {
+	switch key {
+	case glog.MessageKey:
+		return r.msg, true
+	case glog.LevelKey:
+		return strings.ToLower(r.level.String()), true
+	}
+
+	if v, ok := r.attrs[key]; ok {
+		return v, true
+	}
+
+	var cur any = r.attrs
+	for _, part := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func parseTime(v any) time.Time {
+	switch t := v.(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return parsed
+		}
+	case json.Number:
+		n, err := t.Int64()
+		if err != nil {
+			return time.Time{}
+		}
+		// values above 1e11 are millisecond epochs
+		if n > 1e11 {
+			return time.UnixMilli(n)
+		}
+		return time.Unix(n, 0)
+	}
+	return time.Time{}
+}
+
+func formatSource(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case map[string]any:
+		return fmt.Sprintf("%v:%v", s["file"], s["line"])
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,495 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+	"golang.org/x/term"
+)
+
+const (
+	tuiMaxRecords = 50000
+	tuiRedraw     = 100 * time.Millisecond
+	tuiPoll       = 250 * time.Millisecond
+)
+
+type tuiMode int
+
+const (
+	modeNormal tuiMode = iota
+	modeSearch
+	modeWhere
+)
+
+var tuiLevels = []struct {
+	key   byte
+	label string
+	level slog.Level
+}{
+	{'1', "T", glog.LevelTrace},
+	{'2', "D", slog.LevelDebug},
+	{'3', "I", slog.LevelInfo},
+	{'4', "W", slog.LevelWarn},
+	{'5', "E", slog.LevelError},
+	{'6', "F", glog.LevelFatal},
+}
+
+// tui is an interactive viewer that tails records, supports level
+// toggles, free text and attribute filters and expanding a record
+type tui struct {
+	tty    *os.File
+	base   *filter
+	render slog.Handler
+	detail slog.Handler
+	buf    bytes.Buffer
+
+	records []*record
+	visible []*record
+	hidden  map[slog.Level]bool
+	search  string
+	where   map[string]string
+
+	mode     tuiMode
+	input    string
+	selected int
+	expanded bool
+	follow   bool
+	width    int
+	height   int
+}
+
+func runTUI(base *filter, mode glog.ColorMode, files []string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("tui requires a terminal: %w", err)
+	}
+	defer tty.Close()
+
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return err
+	}
+	defer term.Restore(int(tty.Fd()), state)
+
+	t := &tui{
+		tty:    tty,
+		base:   base,
+		hidden: map[slog.Level]bool{},
+		where:  map[string]string{},
+		follow: true,
+	}
+
+	opts := &slog.HandlerOptions{Level: glog.LevelTrace, AddSource: true}
+	t.render = glog.NewColorConsoleHandler(&t.buf, opts, glog.WithColorConsoleColorMode(forceColor(mode)))
+	t.detail = glog.NewColorConsoleHandler(&t.buf, opts,
+		glog.WithColorConsoleColorMode(forceColor(mode)),
+		glog.WithColorConsoleMultiline(true),
+	)
+
+	records := make(chan *record, 1024)
+	keys := make(chan []byte, 16)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if len(files) == 0 {
+		go tailReader(ctx, os.Stdin, records, false)
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		go tailReader(ctx, f, records, true)
+	}
+
+	go readKeys(tty, keys)
+
+	fmt.Fprint(tty, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(tty, "\x1b[?25h\x1b[?1049l")
+
+	ticker := time.NewTicker(tuiRedraw)
+	defer ticker.Stop()
+
+	dirty := true
+	for {
+		select {
+		case rec := <-records:
+			t.add(rec)
+			dirty = true
+		case key, ok := <-keys:
+			if !ok || t.key(key) {
+				return nil
+			}
+			dirty = true
+		case <-ticker.C:
+			if w, h, err := term.GetSize(int(tty.Fd())); err == nil && (w != t.width || h != t.height) {
+				t.width, t.height = w, h
+				dirty = true
+			}
+			if dirty {
+				t.draw()
+				dirty = false
+			}
+		}
+	}
+}
+
+func forceColor(mode glog.ColorMode) glog.ColorMode {
+	if mode == glog.ColorAuto && os.Getenv("NO_COLOR") == "" {
+		return glog.ColorForceOn
+	}
+	return mode
+}
+
+// tailReader decodes records from r, following it for appended data
+// when follow is set
+func tailReader(ctx context.Context, r io.Reader, out chan<- *record, follow bool) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var partial []byte
+
+	for {
+		line, err := br.ReadBytes('\n')
+		partial = append(partial, line...)
+
+		if err == nil {
+			if rec := parseLine(partial); rec != nil {
+				select {
+				case out <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+			partial = partial[:0]
+			continue
+		}
+
+		if !errors.Is(err, io.EOF) || !follow {
+			if rec := parseLine(partial); rec != nil {
+				out <- rec
+			}
+			return
+		}
+
+		select {
+		case <-time.After(tuiPoll):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func parseLine(line []byte) *record {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	if line[0] == '{' {
+		if rec, err := parseRecord(line); err == nil {
+			return rec
+		}
+	}
+	return &record{level: slog.LevelInfo, msg: string(line), raw: string(line), attrs: map[string]any{}}
+}
+
+func readKeys(tty *os.File, keys chan<- []byte) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := tty.Read(buf)
+		if err != nil {
+			return
+		}
+		keys <- append([]byte{}, buf[:n]...)
+	}
+}
+
+func (t *tui) add(rec *record) {
+	t.records = append(t.records, rec)
+	if len(t.records) > tuiMaxRecords {
+		t.records = t.records[len(t.records)-tuiMaxRecords:]
+	}
+
+	if t.match(rec) {
+		t.visible = append(t.visible, rec)
+		if len(t.visible) > tuiMaxRecords {
+			t.visible = t.visible[len(t.visible)-tuiMaxRecords:]
+			t.selected--
+		}
+		if t.follow {
+			t.selected = len(t.visible) - 1
+		}
+	}
+}
+
+func (t *tui) match(rec *record) bool {
+	if t.hidden[rec.level] || !t.base.match(rec) {
+		return false
+	}
+
+	if t.search != "" && !strings.Contains(strings.ToLower(rec.raw), strings.ToLower(t.search)) {
+		return false
+	}
+
+	for key, want := range t.where {
+		v, ok := rec.lookup(key)
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *tui) refilter() {
+	t.visible = t.visible[:0]
+	for _, rec := range t.records {
+		if t.match(rec) {
+			t.visible = append(t.visible, rec)
+		}
+	}
+	t.selected = min(max(t.selected, 0), len(t.visible)-1)
+	if t.follow {
+		t.selected = len(t.visible) - 1
+	}
+}
+
+// key handles a key press and reports whether the viewer should exit
+func (t *tui) key(k []byte) bool {
+	if len(k) == 1 && k[0] == 3 {
+		return true
+	}
+
+	if t.mode != modeNormal {
+		t.editKey(k)
+		return false
+	}
+
+	switch string(k) {
+	case "\x1b[A", "k":
+		t.move(-1)
+	case "\x1b[B", "j":
+		t.move(1)
+	case "\x1b[5~":
+		t.move(-t.pageSize())
+	case "\x1b[6~", " ":
+		t.move(t.pageSize())
+	case "g":
+		t.follow = false
+		t.selected = 0
+	case "G":
+		t.selected = len(t.visible) - 1
+	case "f":
+		t.follow = !t.follow
+		if t.follow {
+			t.selected = len(t.visible) - 1
+		}
+	case "\r", "\n":
+		t.expanded = !t.expanded
+	case "/":
+		t.mode, t.input = modeSearch, t.search
+	case ":":
+		t.mode, t.input = modeWhere, ""
+	case "c":
+		t.search = ""
+		t.where = map[string]string{}
+		t.refilter()
+	case "q":
+		return true
+	default:
+		for _, lv := range tuiLevels {
+			if len(k) == 1 && k[0] == lv.key {
+				t.hidden[lv.level] = !t.hidden[lv.level]
+				t.refilter()
+			}
+		}
+	}
+	return false
+}
+
+func (t *tui) editKey(k []byte) {
+	switch {
+	case len(k) == 1 && k[0] == 27:
+		t.mode = modeNormal
+	case len(k) == 1 && (k[0] == '\r' || k[0] == '\n'):
+		switch t.mode {
+		case modeSearch:
+			t.search = t.input
+		case modeWhere:
+			if key, val, ok := strings.Cut(t.input, "="); ok && key != "" {
+				t.where[key] = val
+			}
+		}
+		t.mode = modeNormal
+		t.refilter()
+	case len(k) == 1 && (k[0] == 127 || k[0] == 8):
+		if r := []rune(t.input); len(r) > 0 {
+			t.input = string(r[:len(r)-1])
+		}
+	case len(k) > 0 && k[0] >= 32 && k[0] != 127:
+		t.input += string(k)
+	}
+}
+
+func (t *tui) move(delta int) {
+	t.selected = min(max(t.selected+delta, 0), len(t.visible)-1)
+	t.follow = t.selected == len(t.visible)-1
+}
+
+func (t *tui) pageSize() int {
+	return max(t.height-2, 1)
+}
+
+// renderRecord formats rec with h and returns the output lines
+func (t *tui) renderRecord(h slog.Handler, rec *record) []string {
+	t.buf.Reset()
+	_ = h.Handle(context.Background(), rec.slogRecord())
+	return strings.Split(strings.TrimRight(t.buf.String(), "\n"), "\n")
+}
+
+func (t *tui) draw() {
+	if t.width == 0 || t.height == 0 {
+		return
+	}
+
+	listHeight := t.height - 1
+	var detail []string
+	if t.expanded && t.selected >= 0 && t.selected < len(t.visible) {
+		detail = t.renderRecord(t.detail, t.visible[t.selected])
+		if len(detail) > listHeight/2 {
+			detail = detail[:listHeight/2]
+		}
+		listHeight -= len(detail) + 1
+	}
+
+	// keep the selection inside the window, anchored to the bottom
+	end := min(len(t.visible), max(t.selected+1, listHeight))
+	start := max(end-listHeight, 0)
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H\x1b[2J")
+
+	for i := start; i < end; i++ {
+		line := t.renderRecord(t.render, t.visible[i])[0]
+		line = fitWidth(line, t.width)
+		if i == t.selected {
+			sb.WriteString("\x1b[7m" + stripANSI(line) + "\x1b[0m")
+		} else {
+			sb.WriteString(line)
+		}
+		sb.WriteString("\r\n")
+	}
+
+	for i := end - start; i < listHeight; i++ {
+		sb.WriteString("\r\n")
+	}
+
+	if detail != nil {
+		sb.WriteString(strings.Repeat("─", t.width) + "\r\n")
+		for _, line := range detail {
+			sb.WriteString(fitWidth(line, t.width) + "\r\n")
+		}
+	}
+
+	sb.WriteString("\x1b[7m" + fitWidth(t.status(), t.width) + "\x1b[0m")
+
+	fmt.Fprint(t.tty, sb.String())
+}
+
+func (t *tui) status() string {
+	switch t.mode {
+	case modeSearch:
+		return "search: " + t.input
+	case modeWhere:
+		return "where key=value: " + t.input
+	}
+
+	var levels []string
+	for _, lv := range tuiLevels {
+		if t.hidden[lv.level] {
+			levels = append(levels, "-")
+		} else {
+			levels = append(levels, lv.label)
+		}
+	}
+
+	var filters []string
+	if t.search != "" {
+		filters = append(filters, "/"+t.search)
+	}
+	for k, v := range t.where {
+		filters = append(filters, k+"="+v)
+	}
+
+	follow := ""
+	if t.follow {
+		follow = " [follow]"
+	}
+
+	return fmt.Sprintf(" %s %d/%d%s %s | 1-6 levels / search : where c clear enter expand f follow q quit",
+		strings.Join(levels, ""), len(t.visible), len(t.records), follow, strings.Join(filters, " "))
+}
+
+// fitWidth pads or cuts s to width visible columns keeping ANSI escapes
+func fitWidth(s string, width int) string {
+	var sb strings.Builder
+	visible := 0
+	inEscape := false
+
+	for _, r := range s {
+		switch {
+		case inEscape:
+			sb.WriteRune(r)
+			if r == 'm' {
+				inEscape = false
+			}
+			continue
+		case r == '\x1b':
+			inEscape = true
+			sb.WriteRune(r)
+			continue
+		case r == '\t':
+			r = ' '
+		}
+
+		if visible >= width {
+			continue
+		}
+		sb.WriteRune(r)
+		visible++
+	}
+
+	if visible < width {
+		sb.WriteString(strings.Repeat(" ", width-visible))
+	}
+	sb.WriteString("\x1b[0m")
+	return sb.String()
+}
+
+func stripANSI(s string) string {
+	var sb strings.Builder
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			inEscape = r != 'm'
+		case r == '\x1b':
+			inEscape = true
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+func runTUI(base *filter, mode glog.ColorMode, files []string) error {
+	return errors.New("tui mode is not supported on windows")
+}
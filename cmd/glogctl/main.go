@@ -0,0 +1,65 @@
+// Command glogctl sends a command to the control socket of a process
+// logging through glog, see glog.WithControlSocket.
+//
+//	glogctl -socket /run/app/glog.sock loggers
+//	glogctl -socket /run/app/glog.sock level db debug
+//	glogctl -socket /run/app/glog.sock focus api db
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	socket := flag.String("socket", os.Getenv("GLOG_SOCKET"), "control socket path, defaults to $GLOG_SOCKET")
+	timeout := flag.Duration("timeout", 5*time.Second, "time to wait for the response")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: glogctl [-socket path] loggers | level <name> <level> | focus <name>... | unfocus | flush")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *socket == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := net.DialTimeout("unix", *socket, *timeout)
+	if err != nil {
+		fail(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(*timeout))
+
+	if _, err := fmt.Fprintln(conn, strings.Join(flag.Args(), " ")); err != nil {
+		fail(err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "ok" {
+			return
+		}
+		if msg, ok := strings.CutPrefix(line, "error: "); ok {
+			fmt.Fprintln(os.Stderr, "glogctl:", msg)
+			os.Exit(1)
+		}
+		fmt.Println(line)
+	}
+	if err := scanner.Err(); err != nil {
+		fail(err)
+	}
+	fail(fmt.Errorf("connection closed before the response ended"))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "glogctl:", err)
+	os.Exit(1)
+}
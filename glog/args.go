@@ -15,6 +15,19 @@ func argsToAttrSlice(args []any) []any {
 	return attrs
 }
 
+func argsToAttrs(args []any) []slog.Attr {
+	var (
+		attr  slog.Attr
+		attrs []slog.Attr
+	)
+	for len(args) > 0 {
+		attr, args = argsToAttr(args)
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
 const badKey = "!BADKEY"
 
 func argsToAttr(args []any) (slog.Attr, []any) {
@@ -23,6 +36,10 @@ func argsToAttr(args []any) (slog.Attr, []any) {
 		if len(args) == 1 {
 			return slog.String(badKey, x), nil
 		}
+		// a slice of attrs after a key is a group, like slog.Group
+		if group, ok := args[1].([]slog.Attr); ok {
+			return slog.Attr{Key: x, Value: slog.GroupValue(group...)}, args[2:]
+		}
 		return slog.Any(x, args[1]), args[2:]
 
 	case slog.Attr:
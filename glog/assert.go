@@ -0,0 +1,26 @@
+package glog
+
+import "log/slog"
+
+// Assert logs msg with args and the stack at Error when cond is false,
+// for cheap invariant checks. In development mode, see WithDevelopment,
+// it panics with msg after flushing.
+//
+//	logger.Assert(len(batch) <= max, "batch over limit", "size", len(batch))
+func (c *BaseLogger) Assert(cond bool, msg string, args ...any) {
+	if cond {
+		return
+	}
+
+	if c.logger.Load().Enabled(c.ctx, slog.LevelError) {
+		// skip runtime.Callers, getStackTrace and Assert
+		stack := c.getStackTrace(callerDepth)
+		args = append(args[:len(args):len(args)], slog.Any(StackKey, stack))
+		c.log(c.ctx, 0, slog.LevelError, msg, args...)
+	}
+
+	if c.getRoot().development {
+		c.getRoot().Flush()
+		panic(msg)
+	}
+}
@@ -0,0 +1,66 @@
+package glog
+
+import "errors"
+
+// treeLoggers returns c and, on the root logger, every logger in the
+// tree, the caller must hold the root lock
+func (c *BaseLogger) treeLoggers() []*BaseLogger {
+	loggers := []*BaseLogger{c}
+	if c.getRoot() == c {
+		for _, logger := range c.loggers {
+			loggers = append(loggers, logger)
+			for _, tenant := range logger.tenantLoggers {
+				loggers = append(loggers, tenant)
+			}
+		}
+		for _, tenant := range c.tenantLoggers {
+			loggers = append(loggers, tenant)
+		}
+	}
+	return loggers
+}
+
+// asyncHandlers returns the async handlers used by c or, on the root
+// logger, by every logger in the tree
+func (c *BaseLogger) asyncHandlers() []*AsyncHandler {
+	root := c.getRoot()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+
+	seen := map[*AsyncHandler]bool{}
+	var handlers []*AsyncHandler
+	for _, logger := range c.treeLoggers() {
+		if logger.async != nil && !seen[logger.async] {
+			seen[logger.async] = true
+			handlers = append(handlers, logger.async)
+		}
+	}
+	return handlers
+}
+
+// Close drains and stops async output, records logged afterwards are
+// dropped, flushes the sinks, see Flush, and closes the outputs opened
+// by Config.Build. On a child logger Close only flushes, its async
+// queue and outputs may be shared with the rest of the tree and are
+// stopped by the root's Close.
+func (c *BaseLogger) Close() error {
+	if c.getRoot() != c {
+		c.Flush()
+		return nil
+	}
+
+	var errs []error
+	for _, h := range c.asyncHandlers() {
+		if err := h.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.Flush()
+	for _, closer := range c.closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.closers = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Typed attribute constructors. Combined with LogAttrs they avoid
+// boxing values into interfaces on hot paths:
+//
+//	logger.LogAttrs(ctx, slog.LevelInfo, "served", glog.Str("path", p), glog.Int("status", 200))
+
+func Str(key, value string) slog.Attr {
+	return slog.String(key, value)
+}
+
+func Int(key string, value int) slog.Attr {
+	return slog.Int(key, value)
+}
+
+func Int64(key string, value int64) slog.Attr {
+	return slog.Int64(key, value)
+}
+
+func Uint64(key string, value uint64) slog.Attr {
+	return slog.Uint64(key, value)
+}
+
+func Float(key string, value float64) slog.Attr {
+	return slog.Float64(key, value)
+}
+
+func Bool(key string, value bool) slog.Attr {
+	return slog.Bool(key, value)
+}
+
+func Dur(key string, value time.Duration) slog.Attr {
+	return slog.Duration(key, value)
+}
+
+func Time(key string, value time.Time) slog.Attr {
+	return slog.Time(key, value)
+}
+
+// Err returns err under ErrorKey, Error and Fatal treat it like an
+// error passed directly
+func Err(err error) slog.Attr {
+	return slog.Any(ErrorKey, err)
+}
+
+func Any(key string, value any) slog.Attr {
+	return slog.Any(key, value)
+}
+
+// LogAttrs logs msg at level with attrs, it is the allocation free
+// alternative to the key value methods
+func (c *BaseLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := c.logger.Load()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(callerDepth-1+c.callerSkip, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+
+	c.handle(ctx, logger, r)
+}
+
+// LogRecord passes r through the handler chain of the logger, for
+// bridges from other logging APIs that build their own records
+func (c *BaseLogger) LogRecord(ctx context.Context, r slog.Record) {
+	logger := c.logger.Load()
+	if !logger.Enabled(ctx, r.Level) {
+		return
+	}
+	c.handle(ctx, logger, r)
+}
@@ -0,0 +1,198 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Mandatory audit fields
+const (
+	AuditActorKey    = "actor"
+	AuditActionKey   = "action"
+	AuditResourceKey = "resource"
+	AuditOutcomeKey  = "outcome"
+)
+
+const (
+	auditKindKey  = "kind"
+	auditKind     = "audit"
+	auditEventKey = "event"
+)
+
+// ErrAuditMissingField is returned by Audit when a mandatory field is absent
+var ErrAuditMissingField = errors.New("audit: missing mandatory field")
+
+var auditRequiredKeys = []string{
+	AuditActorKey,
+	AuditActionKey,
+	AuditResourceKey,
+	AuditOutcomeKey,
+}
+
+// auditPipeline writes audit records as JSON lines to a dedicated sink.
+// It bypasses focus, level filtering and any other record suppression.
+type auditPipeline struct {
+	mu      sync.Mutex
+	out     io.Writer
+	buf     bytes.Buffer
+	handler slog.Handler
+
+	// hash chain state, see WithAuditChain
+	chain       bool
+	anchorEvery int
+	key         []byte
+	prevHash    string
+	count       uint64
+	sinceAnchor int
+	started     bool
+}
+
+func newAuditPipeline(out io.Writer, keys fieldKeys, timeFormat string, loc *time.Location) *auditPipeline {
+	p := &auditPipeline{out: out}
+	p.handler = slog.NewJSONHandler(&p.buf, &slog.HandlerOptions{
+		Level: slog.Level(-1 << 10),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				a.Key = keys.time
+				a.Value = formatTime(a.Value, timeFormat, loc)
+			case slog.LevelKey:
+				return slog.Attr{}
+			case slog.MessageKey:
+				a.Key = auditEventKey
+			}
+			return a
+		},
+	})
+	return p
+}
+
+func (p *auditPipeline) write(ctx context.Context, r slog.Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.chain && !p.started {
+		p.started = true
+		segment := slog.NewRecord(time.Now(), slog.LevelInfo, auditSegmentEvent, 0)
+		segment.AddAttrs(slog.String(auditKindKey, auditSegmentKind))
+		if err := p.writeLocked(ctx, segment, true); err != nil {
+			return err
+		}
+	}
+
+	if err := p.writeLocked(ctx, r, false); err != nil {
+		return err
+	}
+
+	if !p.chain || p.anchorEvery <= 0 {
+		return nil
+	}
+
+	p.sinceAnchor++
+	if p.sinceAnchor >= p.anchorEvery {
+		p.sinceAnchor = 0
+		anchor := slog.NewRecord(time.Now(), slog.LevelInfo, auditAnchorEvent, 0)
+		anchor.AddAttrs(
+			slog.String(auditKindKey, auditAnchorKind),
+			slog.String(auditHeadKey, p.prevHash),
+		)
+		return p.writeLocked(ctx, anchor, true)
+	}
+
+	return nil
+}
+
+// writeLocked writes r, with signed the chain position is signed with
+// the chain key when one is set
+func (p *auditPipeline) writeLocked(ctx context.Context, r slog.Record, signed bool) error {
+	if p.chain {
+		p.count++
+		r.AddAttrs(
+			slog.Uint64(auditSeqKey, p.count),
+			slog.String(auditPrevHashKey, p.prevHash),
+		)
+		if signed && len(p.key) > 0 {
+			r.AddAttrs(slog.String(auditSigKey, signAuditHead(p.key, p.count, p.prevHash)))
+		}
+	}
+
+	p.buf.Reset()
+	if err := p.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	if p.chain {
+		p.prevHash = hashAuditLine(p.buf.Bytes())
+	}
+
+	_, err := p.out.Write(p.buf.Bytes())
+	return err
+}
+
+// auditPipeline returns the root's audit pipeline, creating it on first use
+func (c *BaseLogger) auditPipeline() *auditPipeline {
+	root := c.getRoot()
+	root.auditOnce.Do(func() {
+		out := root.auditOut
+		if out == nil {
+			out = root.stdout
+		}
+		root.audit = newAuditPipeline(out, root.keys, root.timeFormat, root.timeLocation)
+		if root.auditChain {
+			root.audit.chain = true
+			root.audit.anchorEvery = root.auditAnchorEvery
+			root.audit.key = root.auditChainKey
+			root.audit.prevHash = auditGenesisHash
+			// continue the chain of a file written by an earlier process
+			if named, ok := out.(interface{ Name() string }); ok {
+				root.audit.prevHash, root.audit.count = auditChainTail(named.Name())
+			}
+		}
+	})
+	return root.audit
+}
+
+// Audit writes an audit record for event to the audit sink. The args
+// must include the actor, action, resource and outcome fields. Audit
+// records are never sampled, filtered or suppressed.
+func (c *BaseLogger) Audit(event string, args ...any) error {
+	return c.AuditContext(c.ctx, event, args...)
+}
+
+// AuditContext is like Audit but uses ctx for the record
+func (c *BaseLogger) AuditContext(ctx context.Context, event string, args ...any) error {
+	attrs := argsToAttrs(args)
+
+	for _, key := range auditRequiredKeys {
+		if !hasAttrKey(attrs, key) {
+			return fmt.Errorf("%w: %s", ErrAuditMissingField, key)
+		}
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, event, 0)
+	r.AddAttrs(slog.String(auditKindKey, auditKind))
+	if c.name != "" {
+		r.AddAttrs(slog.String(c.keys.logger, c.name))
+	}
+	r.AddAttrs(attrs...)
+
+	return c.auditPipeline().write(ctx, r)
+}
+
+func hasAttrKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
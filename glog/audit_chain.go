@@ -0,0 +1,160 @@
+package glog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	auditSeqKey       = "chain_seq"
+	auditPrevHashKey  = "prev_hash"
+	auditHeadKey      = "chain_head"
+	auditSigKey       = "chain_sig"
+	auditAnchorKind   = "audit_anchor"
+	auditAnchorEvent  = "audit.anchor"
+	auditSegmentKind  = "audit_segment"
+	auditSegmentEvent = "audit.segment"
+)
+
+// maxAuditTail bounds how far back auditChainTail looks for the last
+// record of a file
+const maxAuditTail = 16 * 1024 * 1024
+
+var auditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// ErrAuditChainBroken is returned by VerifyAuditChain when a record
+// does not reference the hash of the record before it
+var ErrAuditChainBroken = errors.New("audit: hash chain broken")
+
+// hashAuditLine returns the hex encoded SHA-256 of an encoded record
+// without its trailing newline
+func hashAuditLine(line []byte) string {
+	sum := sha256.Sum256(bytes.TrimRight(line, "\n"))
+	return hex.EncodeToString(sum[:])
+}
+
+// signAuditHead returns the hex encoded HMAC-SHA256 of the chain
+// position seq and the hash of the record before it
+func signAuditHead(key []byte, seq uint64, head string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.FormatUint(seq, 10) + ":" + head))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditChainTail returns the hash and sequence of the last record of
+// the file at path, the genesis hash and zero when it has none
+func auditChainTail(path string) (string, uint64) {
+	line, err := lastLine(path)
+	if err != nil || len(line) == 0 {
+		return auditGenesisHash, 0
+	}
+
+	var rec struct {
+		Seq uint64 `json:"chain_seq"`
+	}
+	_ = json.Unmarshal(line, &rec)
+	return hashAuditLine(line), rec.Seq
+}
+
+// lastLine returns the last non empty line of the file at path
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// read backwards in growing chunks until a line start is found
+	end := info.Size()
+	for chunk := int64(4096); ; chunk *= 2 {
+		start := max(end-min(chunk, maxAuditTail), 0)
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		buf = bytes.TrimRight(buf, "\r\n ")
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			return buf[i+1:], nil
+		}
+		if start == 0 || chunk >= maxAuditTail {
+			return buf, nil
+		}
+	}
+}
+
+// VerifyAuditChain reads hash chained audit records from r and checks
+// that every record references the hash of the previous one and that
+// anchors match the chain head. A segment record referencing the
+// genesis hash starts a new chain, as written by a process that could
+// not read the end of its sink. It returns the number of records read.
+// Use VerifyAuditChainKey to also check the signatures.
+func VerifyAuditChain(r io.Reader) (int, error) {
+	return VerifyAuditChainKey(r, nil)
+}
+
+// VerifyAuditChainKey is VerifyAuditChain for chains written with
+// WithAuditChainKey. Anchors and segment records must be signed with
+// key, so a rewritten chain or a forged restart is detected.
+func VerifyAuditChainKey(r io.Reader, key []byte) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	prev := auditGenesisHash
+	n := 0
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		n++
+
+		var rec struct {
+			Kind      string `json:"kind"`
+			Seq       uint64 `json:"chain_seq"`
+			PrevHash  string `json:"prev_hash"`
+			ChainHead string `json:"chain_head"`
+			Sig       string `json:"chain_sig"`
+		}
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return n, fmt.Errorf("audit: record %d: %w", n, err)
+		}
+
+		signed := rec.Kind == auditAnchorKind || rec.Kind == auditSegmentKind
+		if signed && len(key) > 0 {
+			want := signAuditHead(key, rec.Seq, rec.PrevHash)
+			if !hmac.Equal([]byte(rec.Sig), []byte(want)) {
+				return n, fmt.Errorf("%w: record %d has an invalid signature", ErrAuditChainBroken, n)
+			}
+		}
+
+		restart := rec.Kind == auditSegmentKind && rec.PrevHash == auditGenesisHash
+		if rec.PrevHash != prev && !restart {
+			return n, fmt.Errorf("%w: record %d", ErrAuditChainBroken, n)
+		}
+
+		if rec.Kind == auditAnchorKind && rec.ChainHead != prev {
+			return n, fmt.Errorf("%w: anchor %d does not match chain head", ErrAuditChainBroken, n)
+		}
+
+		prev = hashAuditLine(line)
+	}
+
+	return n, scanner.Err()
+}
@@ -0,0 +1,204 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAudit(t *testing.T, l *BaseLogger, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		err := l.Audit("user.login",
+			AuditActorKey, "alice",
+			AuditActionKey, "login",
+			AuditResourceKey, "session",
+			AuditOutcomeKey, "success",
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// rechain recomputes the prev_hash of every line after an edit, as
+// someone rewriting the file would
+func rechain(t *testing.T, lines []string) []string {
+	t.Helper()
+	prev := auditGenesisHash
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		rec[auditPrevHashKey] = prev
+		if _, ok := rec[auditHeadKey]; ok {
+			rec[auditHeadKey] = prev
+		}
+		b, _ := json.Marshal(rec)
+		out[i] = string(b)
+		prev = hashAuditLine(b)
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimSpace(s), "\n")
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithAuditChain(2))
+	writeAudit(t, l, 5)
+
+	lines := splitLines(buf.String())
+
+	tests := []struct {
+		name    string
+		lines   []string
+		wantErr bool
+	}{
+		{"intact", lines, false},
+		{"edited", append([]string{lines[0], strings.Replace(lines[1], "alice", "mallory", 1)}, lines[2:]...), true},
+		{"removed", append([]string{lines[0]}, lines[2:]...), true},
+		{"reordered", append([]string{lines[0], lines[2], lines[1]}, lines[3:]...), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := VerifyAuditChain(strings.NewReader(strings.Join(tt.lines, "\n")))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("VerifyAuditChain() = %d, %v, want error %v", n, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrAuditChainBroken) {
+				t.Fatalf("error %v is not ErrAuditChainBroken", err)
+			}
+		})
+	}
+}
+
+func TestAuditChainContinuesAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for run := 0; run < 3; run++ {
+		w, err := NewFileWriter(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := NewLogger(WithAuditOutput(w), WithAuditChain(2))
+		writeAudit(t, l, 3)
+		w.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := VerifyAuditChain(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("VerifyAuditChain() after restarts: %v", err)
+	}
+
+	// per run a segment, three records and an anchor
+	if want := 3 * 5; n != want {
+		t.Fatalf("read %d records, want %d", n, want)
+	}
+
+	var last struct {
+		Seq uint64 `json:"chain_seq"`
+	}
+	lines := splitLines(string(data))
+	json.Unmarshal([]byte(lines[len(lines)-1]), &last)
+	if last.Seq != uint64(n) {
+		t.Fatalf("last chain_seq = %d, want %d", last.Seq, n)
+	}
+}
+
+func TestAuditChainKey(t *testing.T) {
+	key := []byte("audit-key")
+
+	signed := func(t *testing.T) *bytes.Buffer {
+		var buf bytes.Buffer
+		writeAudit(t, NewLogger(WithOutput(&buf), WithAuditChain(2), WithAuditChainKey(key)), 4)
+		// a second process writing to a sink it cannot read restarts
+		writeAudit(t, NewLogger(WithOutput(&buf), WithAuditChain(2), WithAuditChainKey(key)), 2)
+		return &buf
+	}
+
+	t.Run("intact", func(t *testing.T) {
+		if _, err := VerifyAuditChainKey(signed(t), key); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		if _, err := VerifyAuditChainKey(signed(t), []byte("other")); err == nil {
+			t.Fatal("verified with the wrong key")
+		}
+	})
+
+	t.Run("unsigned restart", func(t *testing.T) {
+		buf := signed(t)
+		writeAudit(t, NewLogger(WithOutput(buf), WithAuditChain(2)), 1)
+		if _, err := VerifyAuditChainKey(buf, key); err == nil {
+			t.Fatal("verified a restart without signature")
+		}
+	})
+
+	t.Run("rewritten", func(t *testing.T) {
+		lines := splitLines(signed(t).String())
+		lines[1] = strings.Replace(lines[1], "alice", "mallory", 1)
+		lines = rechain(t, lines)
+
+		data := strings.Join(lines, "\n")
+		if _, err := VerifyAuditChain(strings.NewReader(data)); err != nil {
+			t.Fatalf("rechained file should pass the unkeyed check: %v", err)
+		}
+		if _, err := VerifyAuditChainKey(strings.NewReader(data), key); err == nil {
+			t.Fatal("verified a rewritten chain")
+		}
+	})
+}
+
+func TestAuditChainFileMatchesSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for run := 0; run < 2; run++ {
+		w, err := NewFileWriter(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := NewLogger(WithAuditOutput(w), WithAuditChain(2), WithAuditChainKey([]byte("key")))
+		writeAudit(t, l, 3)
+		w.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := map[string]bool{}
+	for i, line := range splitLines(string(data)) {
+		if err := ValidateAuditRecord([]byte(line)); err != nil {
+			t.Fatalf("line %d %s: %v", i+1, line, err)
+		}
+		var rec struct {
+			Kind string `json:"kind"`
+		}
+		json.Unmarshal([]byte(line), &rec)
+		kinds[rec.Kind] = true
+	}
+
+	for _, kind := range []string{"audit", auditAnchorKind, auditSegmentKind} {
+		if !kinds[kind] {
+			t.Errorf("no %s record in the file", kind)
+		}
+	}
+}
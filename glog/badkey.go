@@ -0,0 +1,58 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// BadKeyMode controls what happens when log args are not well formed
+// key value pairs, e.g. a key without a value or a non string key
+type BadKeyMode int
+
+const (
+	// BadKeyIgnore logs the offending values under "!BADKEY" like slog
+	BadKeyIgnore BadKeyMode = iota
+	// BadKeyWarn also emits a warning record with the call site
+	BadKeyWarn
+	// BadKeyPanic panics, meant for development and tests
+	BadKeyPanic
+)
+
+// badKeyValues returns the values logged under badKey
+func badKeyValues(attrs []slog.Attr) []any {
+	var values []any
+	for _, a := range attrs {
+		if a.Key == badKey {
+			values = append(values, a.Value.Any())
+		}
+	}
+	return values
+}
+
+// checkBadKeys reports malformed args passed at pc according to the
+// logger's BadKeyMode, msg is empty for args passed to With
+func (c *BaseLogger) checkBadKeys(ctx context.Context, logger *slog.Logger, pc uintptr, msg string, attrs []slog.Attr) {
+	if c.badKeys == BadKeyIgnore {
+		return
+	}
+
+	values := badKeyValues(attrs)
+	if len(values) == 0 {
+		return
+	}
+
+	if c.badKeys == BadKeyPanic {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		panic(fmt.Sprintf("glog: malformed key value args %v for %q at %s:%d", values, msg, frame.File, frame.Line))
+	}
+
+	warn := slog.NewRecord(time.Now(), slog.LevelWarn, "malformed log args", pc)
+	if msg != "" {
+		warn.AddAttrs(slog.String("record_msg", msg))
+	}
+	warn.AddAttrs(slog.Any("bad_values", values))
+	c.handle(ctx, logger, warn)
+}
@@ -0,0 +1,158 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Burst describes more than the allowed number of error records
+// within a window
+type Burst struct {
+	Key    string        `json:"key,omitempty"`
+	Count  int           `json:"count"`
+	Window time.Duration `json:"window"`
+	First  time.Time     `json:"first"`
+	Last   time.Time     `json:"last"`
+	// Record is the record that triggered the burst
+	Record Record `json:"-"`
+}
+
+// BurstKey groups records for burst detection, records with different
+// keys are counted separately
+type BurstKey func(r Record) string
+
+// BurstByLogger counts bursts per logger name
+func BurstByLogger(r Record) string {
+	return r.Logger
+}
+
+// BurstByFingerprint counts bursts per message and call site
+func BurstByFingerprint(r Record) string {
+	if r.Source == nil {
+		return r.Message
+	}
+	return fmt.Sprintf("%s@%s:%d", r.Message, r.Source.File, r.Source.Line)
+}
+
+// burstDetector tracks error times per key, see WithErrorBurst
+type burstDetector struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	key       BurstKey
+	fn        func(Burst)
+	times     map[string][]time.Time
+	// swept is when keys without errors in the window were last removed
+	swept time.Time
+}
+
+func (d *burstDetector) observe(rec Record) {
+	var key string
+	if d.key != nil {
+		key = d.key(rec)
+	}
+
+	d.mu.Lock()
+	cutoff := rec.Time.Add(-d.window)
+	d.sweep(rec.Time, cutoff)
+	times := d.times[key]
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	times = append(times[i:], rec.Time)
+
+	if len(times) <= d.threshold {
+		d.times[key] = times
+		d.mu.Unlock()
+		return
+	}
+
+	// start over so the next alert needs a new burst
+	delete(d.times, key)
+	d.mu.Unlock()
+
+	// the callback may log, run it outside the logging call
+	go d.fn(Burst{
+		Key:    key,
+		Count:  len(times),
+		Window: d.window,
+		First:  times[0],
+		Last:   rec.Time,
+		Record: rec,
+	})
+}
+
+// sweep removes the keys whose errors all fell out of the window, at
+// most once per window so the cost is spread over many records
+func (d *burstDetector) sweep(now, cutoff time.Time) {
+	if now.Sub(d.swept) < d.window {
+		return
+	}
+	d.swept = now
+	for key, times := range d.times {
+		if times[len(times)-1].Before(cutoff) {
+			delete(d.times, key)
+		}
+	}
+}
+
+// WithErrorBurst calls fn when more than threshold records at Error or
+// above are logged within window by the loggers of the tree. If key is
+// not nil records are counted per key, see BurstByLogger and
+// BurstByFingerprint. fn runs on its own goroutine. threshold and
+// window must be positive and fn must not be nil, NewLoggerE reports
+// them otherwise.
+func WithErrorBurst(threshold int, window time.Duration, key BurstKey, fn func(Burst)) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithErrorBurst") {
+			return
+		}
+		switch {
+		case threshold <= 0:
+			bl.errs = append(bl.errs, fmt.Errorf("glog: error burst threshold must be positive, got %d", threshold))
+			return
+		case window <= 0:
+			bl.errs = append(bl.errs, fmt.Errorf("glog: error burst window must be positive, got %v", window))
+			return
+		case fn == nil:
+			bl.errs = append(bl.errs, errors.New("glog: error burst callback is nil"))
+			return
+		}
+		d := &burstDetector{
+			threshold: threshold,
+			window:    window,
+			key:       key,
+			fn:        fn,
+			times:     map[string][]time.Time{},
+		}
+		bl.subs.observe(func(r Record) bool { return r.Level >= slog.LevelError }, d.observe)
+	}
+}
+
+// BurstWebhook returns a burst callback that posts the burst as JSON
+// to url, along with the message and logger of the triggering record
+func BurstWebhook(url string) func(Burst) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(b Burst) {
+		body, err := json.Marshal(struct {
+			Burst
+			Message string `json:"message"`
+			Logger  string `json:"logger,omitempty"`
+		}{b, b.Record.Message, b.Record.Logger})
+		if err != nil {
+			return
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,85 @@
+package glog
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWithErrorBurstValidation(t *testing.T) {
+	fn := func(Burst) {}
+
+	tests := []struct {
+		name      string
+		threshold int
+		window    time.Duration
+		fn        func(Burst)
+		wantErr   bool
+	}{
+		{"valid", 3, time.Second, fn, false},
+		{"zero threshold", 0, time.Second, fn, true},
+		{"negative threshold", -1, time.Second, fn, true},
+		{"zero window", 3, 0, fn, true},
+		{"nil callback", 3, time.Second, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoggerE(WithOutput(io.Discard), WithErrorBurst(tt.threshold, tt.window, nil, tt.fn))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("NewLoggerE() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithErrorBurstFires(t *testing.T) {
+	bursts := make(chan Burst, 4)
+	l := NewLogger(WithOutput(io.Discard),
+		WithErrorBurst(2, time.Minute, BurstByLogger, func(b Burst) { bursts <- b }))
+
+	l.Error("one")
+	l.Error("two")
+	l.GetLogger("other").Error("three")
+
+	select {
+	case b := <-bursts:
+		t.Fatalf("burst at %d errors, threshold is 2", b.Count)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	l.Error("four")
+	select {
+	case b := <-bursts:
+		if b.Count != 3 || b.Record.Message != "four" {
+			t.Fatalf("burst = %+v", b)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no burst after threshold was exceeded")
+	}
+}
+
+func TestBurstDetectorEvictsKeys(t *testing.T) {
+	d := &burstDetector{
+		threshold: 100,
+		window:    time.Minute,
+		key:       func(r Record) string { return r.Message },
+		fn:        func(Burst) {},
+		times:     map[string][]time.Time{},
+	}
+
+	now := time.Now()
+	for i := 0; i < 50; i++ {
+		d.observe(Record{Level: slog.LevelError, Message: fmt.Sprint(i), Time: now})
+	}
+	if len(d.times) != 50 {
+		t.Fatalf("tracking %d keys, want 50", len(d.times))
+	}
+
+	d.observe(Record{Level: slog.LevelError, Message: "later", Time: now.Add(2 * time.Minute)})
+	if len(d.times) != 1 {
+		t.Fatalf("tracking %d keys after the window passed, want 1", len(d.times))
+	}
+}
@@ -0,0 +1,46 @@
+package glog
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode controls whether the pretty handler emits ANSI colors
+type ColorMode int
+
+const (
+	// ColorAuto enables colors only when the output is a terminal
+	// and neither NO_COLOR nor TERM=dumb are set
+	ColorAuto ColorMode = iota
+	// ColorForceOn always emits colors
+	ColorForceOn
+	// ColorForceOff never emits colors
+	ColorForceOff
+)
+
+// shouldColor resolves mode for the given output writer
+func shouldColor(mode ColorMode, out io.Writer) bool {
+	switch mode {
+	case ColorForceOn:
+		return true
+	case ColorForceOff:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	f, ok := out.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
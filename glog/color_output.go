@@ -0,0 +1,11 @@
+//go:build !windows
+
+package glog
+
+import "io"
+
+// prepareColorOutput returns a writer able to render ANSI escape
+// sequences, terminals on this platform support them natively
+func prepareColorOutput(out io.Writer) io.Writer {
+	return out
+}
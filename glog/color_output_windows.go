@@ -0,0 +1,38 @@
+//go:build windows
+
+package glog
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-colorable"
+	"golang.org/x/sys/windows"
+)
+
+// prepareColorOutput enables virtual terminal processing on the console
+// behind out, falling back to a writer that translates ANSI escape
+// sequences into console API calls on older Windows versions
+func prepareColorOutput(out io.Writer) io.Writer {
+	f, ok := out.(*os.File)
+	if !ok {
+		return out
+	}
+
+	handle := windows.Handle(f.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return out
+	}
+
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return out
+	}
+
+	if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err == nil {
+		return out
+	}
+
+	return colorable.NewColorable(f)
+}
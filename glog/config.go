@@ -0,0 +1,167 @@
+package glog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config describes a logger so it can be loaded from a configuration
+// file or the environment, e.g. with koanf, viper or envconfig
+//
+//	level: info
+//	format: json
+//	outputs: [stdout, /var/log/app.log]
+//	sampling: {debug: 0.01, info: 0.1}
+//	redact: [password, authorization]
+type Config struct {
+	// Level is the minimum level, e.g. "debug", defaults to DefaultLogLevel
+	Level string `json:"level" yaml:"level" mapstructure:"level"`
+	// Format is one of "json", "console" or "pretty", defaults to "json"
+	Format string `json:"format" yaml:"format" mapstructure:"format"`
+	// Outputs are "stdout", "stderr" or file paths, defaults to stdout
+	Outputs []string `json:"outputs" yaml:"outputs" mapstructure:"outputs"`
+	// Name is the name of the root logger
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+	// Focus restricts output to the named loggers
+	Focus []string `json:"focus" yaml:"focus" mapstructure:"focus"`
+	// Sampling maps level names to the fraction of records kept
+	Sampling map[string]float64 `json:"sampling" yaml:"sampling" mapstructure:"sampling"`
+	// SamplingInterval is how often sampling summaries are logged
+	SamplingInterval time.Duration `json:"sampling_interval" yaml:"sampling_interval" mapstructure:"sampling_interval"`
+	// Redact lists attribute keys whose values are hidden
+	Redact []string `json:"redact" yaml:"redact" mapstructure:"redact"`
+	// Color is one of "auto", "always" or "never" for the pretty format
+	Color string `json:"color" yaml:"color" mapstructure:"color"`
+}
+
+// Build validates the configuration and creates the logger. options
+// are applied after the configuration. Files opened for outputs are
+// closed by the logger's Close method.
+func (cfg Config) Build(options ...Option) (*BaseLogger, error) {
+	var opts []Option
+	var errs []error
+
+	if cfg.Level != "" {
+		if _, err := ParseLevel(cfg.Level); err != nil {
+			errs = append(errs, err)
+		}
+		opts = append(opts, WithLevel(cfg.Level))
+	}
+
+	switch strings.ToLower(cfg.Format) {
+	case "", LoggerTypeJSON:
+		opts = append(opts, WithLoggerTypeJSON())
+	case LoggerTypeConsole, "text":
+		opts = append(opts, WithLoggerTypeConsole())
+	case LoggerTypePretty:
+		opts = append(opts, WithLoggerTypePretty())
+	default:
+		errs = append(errs, fmt.Errorf("glog: unknown format %q", cfg.Format))
+	}
+
+	if cfg.Name != "" {
+		opts = append(opts, WithName(cfg.Name))
+	}
+
+	switch strings.ToLower(cfg.Color) {
+	case "", "auto":
+	case "always":
+		opts = append(opts, WithColor(ColorForceOn))
+	case "never":
+		opts = append(opts, WithColor(ColorForceOff))
+	default:
+		errs = append(errs, fmt.Errorf("glog: unknown color mode %q", cfg.Color))
+	}
+
+	if len(cfg.Sampling) > 0 {
+		rates, err := parseSampling(cfg.Sampling)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		opts = append(opts, WithSampling(rates, cfg.SamplingInterval))
+	}
+
+	if len(cfg.Redact) > 0 {
+		opts = append(opts, WithRedactKeys(cfg.Redact...))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	out, closers, err := openOutputs(cfg.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithOutput(out))
+
+	logger, err := NewLoggerE(append(opts, options...)...)
+	if err != nil {
+		for _, c := range closers {
+			c.Close()
+		}
+		return nil, err
+	}
+	logger.closers = append(logger.closers, closers...)
+
+	if len(cfg.Focus) > 0 {
+		logger.Focus(cfg.Focus...)
+	}
+
+	return logger, nil
+}
+
+func parseSampling(sampling map[string]float64) (map[slog.Level]float64, error) {
+	rates := make(map[slog.Level]float64, len(sampling))
+	for name, rate := range sampling {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("glog: sampling rate for %s must be between 0 and 1, got %v", name, rate)
+		}
+		rates[level] = rate
+	}
+	return rates, nil
+}
+
+// openOutputs resolves output names to a writer, opened files are returned
+// so they can be closed with the logger
+func openOutputs(outputs []string) (io.Writer, []io.Closer, error) {
+	if len(outputs) == 0 {
+		return os.Stdout, nil, nil
+	}
+
+	var writers []io.Writer
+	var closers []io.Closer
+
+	for _, output := range outputs {
+		switch output {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			w, err := NewFileWriter(output)
+			if err != nil {
+				for _, c := range closers {
+					c.Close()
+				}
+				return nil, nil, fmt.Errorf("glog: open output %q: %w", output, err)
+			}
+			writers = append(writers, w)
+			closers = append(closers, w)
+		}
+	}
+
+	if len(writers) == 1 {
+		return writers[0], closers, nil
+	}
+	return io.MultiWriter(writers...), closers, nil
+}
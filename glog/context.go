@@ -0,0 +1,40 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type ctxAttrsKey struct{}
+
+// AppendCtx returns a copy of ctx carrying attrs in addition to the
+// attributes already stored in it. Records logged with the returned
+// context, or any context derived from it, include them.
+//
+//	ctx = glog.AppendCtx(ctx, slog.String("request_id", id))
+//	logger.WithContext(ctx).Info("handled")
+func AppendCtx(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := CtxAttrs(ctx)
+	return context.WithValue(ctx, ctxAttrsKey{}, append(slices.Clip(prev), attrs...))
+}
+
+// CtxAttrs returns the attributes stored in ctx with AppendCtx
+func CtxAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextEnricher adds the attributes stored in the record's context
+// with AppendCtx
+func ContextEnricher() Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		return CtxAttrs(ctx)
+	}
+}
@@ -0,0 +1,232 @@
+package glog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ControlRoot names the root logger in control commands
+const ControlRoot = "root"
+
+// ControlServer accepts control commands on a unix socket, one command
+// per line:
+//
+//	loggers               list loggers and their levels
+//	level <name> <level>  set the level of a logger, "root" for the root
+//	focus <name>...       restrict output to the named loggers
+//	unfocus               restore output for all loggers
+//	flush                 write queued and buffered records
+//
+// Every response ends with a line "ok" or "error: <reason>". The
+// glogctl command sends commands from a shell.
+type ControlServer struct {
+	logger   *BaseLogger
+	listener net.Listener
+	path     string
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+	done  bool
+}
+
+// ServeControl listens on the unix socket at path and serves control
+// commands for the root logger of c until Close is called. The socket
+// is only accessible by the owner of the process. A socket left at
+// path by a process that exited is replaced, one that still accepts
+// connections is an error.
+func (c *BaseLogger) ServeControl(path string) (*ControlServer, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, fmt.Errorf("glog: control socket: %w", err)
+	}
+
+	l, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("glog: control socket: %w", err)
+	}
+
+	s := &ControlServer{
+		logger:   c.getRoot(),
+		listener: l,
+		path:     path,
+		conns:    map[net.Conn]bool{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting commands, closes open connections and removes
+// the socket
+func (s *ControlServer) Close() error {
+	s.mu.Lock()
+	s.done = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	s.wg.Wait()
+	os.Remove(s.path)
+	return err
+}
+
+// removeStaleSocket removes the socket at path when no process
+// accepts connections on it
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		// nothing there, or not a socket and Listen reports it
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// listenPrivate listens on a socket created in a directory only the
+// owner can enter and moves it to path once it is 0600, so it is never
+// reachable by other users
+func listenPrivate(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".glogctl-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// Close removes path instead of the temporary name
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *ControlServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.done {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *ControlServer) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	w := bufio.NewWriter(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if err := s.exec(w, fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(w, "error: %v\n", err)
+		} else {
+			fmt.Fprintln(w, "ok")
+		}
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *ControlServer) exec(w io.Writer, cmd string, args []string) error {
+	root := s.logger
+
+	switch cmd {
+	case "loggers":
+		for _, name := range append([]string{ControlRoot}, root.LoggerNames()...) {
+			fmt.Fprintf(w, "%s %s\n", name, levelLabel(root.namedLogger(name).Level()))
+		}
+		return nil
+
+	case "level":
+		if len(args) != 2 {
+			return errors.New("usage: level <name> <level>")
+		}
+		logger := root.namedLogger(args[0])
+		if logger == nil {
+			return fmt.Errorf("unknown logger %q", args[0])
+		}
+		if _, err := ParseLevel(args[1]); err != nil {
+			return fmt.Errorf("unknown level %q", args[1])
+		}
+		logger.WithLevel(args[1])
+		return nil
+
+	case "focus":
+		if len(args) == 0 {
+			return errors.New("usage: focus <name>...")
+		}
+		root.Focus(args...)
+		return nil
+
+	case "unfocus":
+		root.Unfocus()
+		return nil
+
+	case "flush":
+		root.Flush()
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+// namedLogger returns the root for ControlRoot or its child named
+// name, nil if there is none
+func (c *BaseLogger) namedLogger(name string) *BaseLogger {
+	if name == ControlRoot {
+		return c
+	}
+	logger, _ := c.Lookup(name)
+	return logger
+}
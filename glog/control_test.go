@@ -0,0 +1,115 @@
+package glog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// control sends cmd to the socket at path and returns the response
+func control(t *testing.T, path, cmd string) string {
+	t.Helper()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, cmd+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		line := sc.Text()
+		out.WriteString(line + "\n")
+		if line == "ok" || strings.HasPrefix(line, "error:") {
+			break
+		}
+	}
+	return out.String()
+}
+
+func TestControlSocketServesAfterConfiguration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+	l, err := NewLoggerE(WithOutput(io.Discard), WithControlSocket(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.GetLogger("db")
+
+	got := control(t, path, "loggers")
+	if !strings.Contains(got, "db") || !strings.HasSuffix(got, "ok\n") {
+		t.Fatalf("loggers = %q", got)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("socket mode = %v, want 0600", perm)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Fatalf("left %d entries next to the socket, want 1", len(entries))
+	}
+
+	l.Close()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Fatalf("Close left the socket: %v", err)
+	}
+}
+
+func TestControlSocketExisting(t *testing.T) {
+	tests := []struct {
+		name    string
+		live    bool
+		wantErr bool
+	}{
+		{"stale socket is replaced", false, false},
+		{"live socket is kept", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ctl.sock")
+			other, err := net.Listen("unix", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.live {
+				defer other.Close()
+			} else {
+				// leave the file behind as a crashed process would
+				other.(*net.UnixListener).SetUnlinkOnClose(false)
+				other.Close()
+			}
+
+			l, err := NewLoggerE(WithOutput(io.Discard), WithControlSocket(path))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("NewLoggerE() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				defer l.Close()
+				if got := control(t, path, "loggers"); !strings.HasSuffix(got, "ok\n") {
+					t.Fatalf("loggers = %q", got)
+				}
+				return
+			}
+
+			if _, err := os.Lstat(path); err != nil {
+				t.Fatalf("removed the live socket: %v", err)
+			}
+			conn, err := net.Dial("unix", path)
+			if err != nil {
+				t.Fatalf("live socket no longer accepts: %v", err)
+			}
+			conn.Close()
+		})
+	}
+}
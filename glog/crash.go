@@ -0,0 +1,199 @@
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// CrashReport is the content of a crash bundle written by Fatal or
+// CatchPanic when WithCrashDir is set
+type CrashReport struct {
+	Time    time.Time      `json:"time"`
+	Message string         `json:"message"`
+	Logger  string         `json:"logger,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Panic   string         `json:"panic,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Stack   []CrashFrame   `json:"stack"`
+	Recent  []CrashRecord  `json:"recent,omitempty"`
+	Build   map[string]any `json:"build,omitempty"`
+	Host    map[string]any `json:"host,omitempty"`
+}
+
+// CrashFrame is a stack frame of a crash report
+type CrashFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (f CrashFrame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// CrashRecord is a recent record included in a crash report
+type CrashRecord struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Logger  string         `json:"logger,omitempty"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// CatchPanic writes a crash bundle and logs the panic at Fatal if the
+// calling goroutine is panicking, then panics again so the process
+// still crashes. It must be deferred directly:
+//
+//	defer logger.CatchPanic()
+func (c *BaseLogger) CatchPanic() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	msg := "panic"
+	args := []any{slog.Any("panic", v)}
+	if err, ok := v.(error); ok {
+		args = []any{Err(err)}
+	}
+
+	stack := PanicStack()
+
+	r := slog.NewRecord(time.Now(), LevelFatal, msg, PanicPC())
+	r.AddAttrs(argsToAttrs(args)...)
+	c.LogRecord(c.ctx, r)
+
+	c.writeCrash(msg, args, fmt.Sprint(v), stack)
+	c.getRoot().Flush()
+
+	panic(v)
+}
+
+// PanicPC returns the PC of the panicking function, for the record of
+// a recovered panic. It must be called from the deferred function that
+// recovered.
+func PanicPC() uintptr {
+	var pcs [32]uintptr
+	// skip runtime.Callers, PanicPC and the deferred function
+	n := runtime.Callers(3, pcs[:])
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			return pc
+		}
+	}
+	return 0
+}
+
+// PanicStack returns the stack of the panicking goroutine with the
+// panicking function on top. Like PanicPC, it must be called from the
+// deferred function that recovered.
+func PanicStack() []CrashFrame {
+	// skip runtime.Callers, crashStack, PanicStack and the deferred
+	// function, then the runtime panic frames
+	stack := crashStack(4)
+	for len(stack) > 1 && strings.HasPrefix(stack[0].Function, "runtime.") {
+		stack = stack[1:]
+	}
+	return stack
+}
+
+// crashStack captures the stack of the caller skipping skip frames
+func crashStack(skip int) []CrashFrame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var out []CrashFrame
+	for {
+		frame, more := frames.Next()
+		out = append(out, CrashFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// writeCrash writes the crash bundle for the record msg with args,
+// errors are reported on stderr since the logger may be unusable
+func (c *BaseLogger) writeCrash(msg string, args []any, panicValue string, stack []CrashFrame) {
+	if c.crashDir == "" {
+		return
+	}
+
+	now := time.Now()
+	report := CrashReport{
+		Time:    now,
+		Message: msg,
+		Logger:  c.name,
+		Panic:   panicValue,
+		Stack:   stack,
+		Build:   attrsToMap(BuildAttrs()),
+		Host:    attrsToMap(HostAttrs()),
+	}
+
+	err, rest := findError(args)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	report.Attrs = attrsToMap(argsToAttrs(rest))
+
+	for _, rec := range c.getRoot().Recent(-1) {
+		report.Recent = append(report.Recent, CrashRecord{
+			Time:    rec.Time,
+			Level:   levelLabel(rec.Level),
+			Logger:  rec.Logger,
+			Message: rec.Message,
+			Attrs:   attrsToMap(rec.Attrs),
+		})
+	}
+
+	name := fmt.Sprintf("crash-%s-%d.json", now.UTC().Format("20060102T150405.000"), os.Getpid())
+	path := filepath.Join(c.crashDir, name)
+	if err := writeCrashFile(path, report); err != nil {
+		fmt.Fprintf(os.Stderr, "glog: write crash report: %v\n", err)
+	}
+}
+
+func writeCrashFile(path string, report CrashReport) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// attrsToMap converts attrs to a map for JSON encoding, groups become
+// nested maps and errors their message
+func attrsToMap(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindGroup:
+			out[a.Key] = attrsToMap(v.Group())
+		case slog.KindAny:
+			if err, ok := v.Any().(error); ok {
+				out[a.Key] = err.Error()
+				continue
+			}
+			out[a.Key] = v.Any()
+		default:
+			out[a.Key] = v.Any()
+		}
+	}
+	return out
+}
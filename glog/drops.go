@@ -0,0 +1,143 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons reported for dropped records
+const (
+	DropReasonSampled     = "sampled"
+	DropReasonQueueFull   = "queue_full"
+	DropReasonClosed      = "closed"
+	DropReasonSinkError   = "sink_error"
+	DropReasonCircuitOpen = "circuit_open"
+)
+
+// DroppedKey groups the per reason counts of the drop report record
+const DroppedKey = "dropped"
+
+// dropStats counts the records dropped by a logger tree per reason
+type dropStats struct {
+	mu     sync.RWMutex
+	counts map[string]*dropCount
+
+	// interval between reports, zero disables them
+	interval   time.Duration
+	pending    atomic.Uint64
+	lastReport atomic.Int64
+	reporting  atomic.Bool
+}
+
+// dropCount holds the total drops for a reason and the drops not yet reported
+type dropCount struct {
+	total   atomic.Uint64
+	pending atomic.Uint64
+}
+
+func newDropStats() *dropStats {
+	d := &dropStats{counts: map[string]*dropCount{}}
+	d.lastReport.Store(time.Now().UnixNano())
+	return d
+}
+
+func (d *dropStats) add(reason string) {
+	d.mu.RLock()
+	n, ok := d.counts[reason]
+	d.mu.RUnlock()
+
+	if !ok {
+		d.mu.Lock()
+		if n, ok = d.counts[reason]; !ok {
+			n = &dropCount{}
+			d.counts[reason] = n
+		}
+		d.mu.Unlock()
+	}
+
+	n.total.Add(1)
+	n.pending.Add(1)
+	d.pending.Add(1)
+}
+
+// totals returns the number of records dropped per reason
+func (d *dropStats) totals() map[string]uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	out := make(map[string]uint64, len(d.counts))
+	for reason, n := range d.counts {
+		out[reason] = n.total.Load()
+	}
+	return out
+}
+
+// due reports whether records were dropped and the report interval elapsed
+func (d *dropStats) due(now time.Time) bool {
+	return d.interval > 0 &&
+		d.pending.Load() > 0 &&
+		now.UnixNano()-d.lastReport.Load() >= int64(d.interval)
+}
+
+// report logs the drops since the previous report through handler,
+// concurrent callers skip the report instead of waiting
+func (d *dropStats) report(ctx context.Context, handler slog.Handler, now time.Time) {
+	if !d.reporting.CompareAndSwap(false, true) {
+		return
+	}
+	defer d.reporting.Store(false)
+
+	if !d.due(now) {
+		return
+	}
+	d.lastReport.Store(now.UnixNano())
+	total := d.pending.Swap(0)
+
+	var counts []slog.Attr
+	d.mu.RLock()
+	for reason, n := range d.counts {
+		if pending := n.pending.Swap(0); pending > 0 {
+			counts = append(counts, slog.Uint64(reason, pending))
+		}
+	}
+	d.mu.RUnlock()
+
+	r := slog.NewRecord(now, slog.LevelWarn, "records dropped", 0)
+	r.AddAttrs(
+		slog.Uint64("count", total),
+		slog.Any(DroppedKey, slog.GroupValue(counts...)),
+	)
+	if err := handler.Handle(ctx, r); err != nil {
+		d.add(dropReasonFor(err))
+	}
+}
+
+func dropReasonFor(err error) string {
+	if errors.Is(err, ErrAsyncClosed) {
+		return DropReasonClosed
+	}
+	return DropReasonSinkError
+}
+
+// handle passes r to the handler of logger, counting failures as drops
+// and emitting the drop report when due
+func (c *BaseLogger) handle(ctx context.Context, logger *slog.Logger, r slog.Record) {
+	if err := logger.Handler().Handle(ctx, r); err != nil {
+		c.drops.add(dropReasonFor(err))
+	}
+
+	if c.drops.due(r.Time) {
+		// below focus, sampling and the breaker, which would drop it
+		c.drops.report(ctx, c.getRoot().sink.Load().Handler(), r.Time)
+	}
+}
+
+// Dropped returns the number of records dropped by the logger tree per
+// reason since it was created, e.g. DropReasonSampled
+func (c *BaseLogger) Dropped() map[string]uint64 {
+	return c.drops.totals()
+}
@@ -0,0 +1,40 @@
+package glog
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDropReportBypassesFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates map[slog.Level]float64
+		focus bool
+	}{
+		{"sampled", map[slog.Level]float64{slog.LevelInfo: 0.5, slog.LevelWarn: 0}, false},
+		{"focused", map[slog.Level]float64{slog.LevelInfo: 0.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &flushWriter{}
+			l := NewLogger(WithOutput(out), WithDropReport(time.Millisecond), WithSampling(tt.rates, 0))
+			api := l.GetLogger("api")
+			if tt.focus {
+				l.Focus("api")
+			}
+
+			// the second record is sampled out, the third reports it
+			api.Info("first")
+			api.Info("second")
+			time.Sleep(2 * time.Millisecond)
+			api.Info("third")
+
+			if !strings.Contains(out.String(), "records dropped") {
+				t.Fatalf("drop report was filtered out:\n%s", out.String())
+			}
+		})
+	}
+}
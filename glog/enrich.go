@@ -0,0 +1,130 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var hostAttrs = sync.OnceValue(func() []slog.Attr {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return []slog.Attr{
+		slog.String("hostname", hostname),
+		slog.Int("pid", os.Getpid()),
+		slog.String("go_version", runtime.Version()),
+	}
+})
+
+// HostAttrs returns the hostname, pid and go_version attributes.
+// Values are resolved once per process.
+func HostAttrs() []slog.Attr {
+	return slices.Clone(hostAttrs())
+}
+
+var buildAttrs = sync.OnceValue(func() []slog.Attr {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	var attrs []slog.Attr
+	if v := info.Main.Version; v != "" {
+		attrs = append(attrs, slog.String("module.version", v))
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time":
+			attrs = append(attrs, slog.String(setting.Key, setting.Value))
+		}
+	}
+
+	return attrs
+})
+
+// BuildAttrs returns the module version and VCS revision and time
+// embedded in the binary, missing values are omitted.
+// Values are resolved once per process.
+func BuildAttrs() []slog.Attr {
+	return slices.Clone(buildAttrs())
+}
+
+// GoroutineEnricher adds the calling goroutine's ID as "goroutine".
+// It parses runtime.Stack on every record and is meant for debugging
+// concurrency issues in development builds only.
+func GoroutineEnricher() Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		return []slog.Attr{slog.Uint64("goroutine", goroutineID())}
+	}
+}
+
+// goroutineID extracts the ID from the "goroutine N [status]:" header
+// of the current goroutine's stack trace, zero if it can't be parsed
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// SequenceEnricher adds a "seq" attribute incremented atomically for
+// every record handled, so consumers can detect gaps and reordering
+func SequenceEnricher(counter *atomic.Uint64) Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		return []slog.Attr{slog.Uint64("seq", counter.Add(1))}
+	}
+}
+
+// UptimeEnricher adds an "uptime" attribute with the time elapsed since start
+func UptimeEnricher(start time.Time) Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		return []slog.Attr{slog.Duration("uptime", r.Time.Sub(start))}
+	}
+}
+
+// AttrProvider returns attributes computed at log time from ctx, such
+// as a feature flag snapshot or the number of active requests
+type AttrProvider func(ctx context.Context) []slog.Attr
+
+// ProviderEnricher adapts provider to an Enricher
+func ProviderEnricher(provider AttrProvider) Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		return provider(ctx)
+	}
+}
+
+// HeapProvider adds a "heap_bytes" attribute with the memory occupied
+// by live and not yet swept heap objects. It reads runtime/metrics
+// which, unlike runtime.ReadMemStats, does not stop the world.
+func HeapProvider() AttrProvider {
+	return func(ctx context.Context) []slog.Attr {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return nil
+		}
+		return []slog.Attr{slog.Uint64("heap_bytes", sample[0].Value.Uint64())}
+	}
+}
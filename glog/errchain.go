@@ -0,0 +1,35 @@
+package glog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// ErrorChainKey groups the links of the unwrap chain of a logged error,
+// keyed by position with the outermost error at "0"
+const ErrorChainKey = "error_chain"
+
+// errorChain returns the unwrap chain of err outermost first
+func errorChain(err error) []error {
+	var chain []error
+	for err != nil {
+		chain = append(chain, err)
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// errorChainAttr renders chain as a group with the message and the
+// dynamic type of each link
+func errorChainAttr(chain []error) slog.Attr {
+	links := make([]slog.Attr, 0, len(chain))
+	for i, err := range chain {
+		links = append(links, slog.Group(strconv.Itoa(i),
+			slog.String("msg", err.Error()),
+			slog.String("type", fmt.Sprintf("%T", err)),
+		))
+	}
+	return slog.Any(ErrorChainKey, slog.GroupValue(links...))
+}
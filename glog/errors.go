@@ -0,0 +1,79 @@
+package glog
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+)
+
+// stackError is an error that records the stack where it was created
+// and attrs describing it, see NewError and Wrap
+type stackError struct {
+	msg   string
+	err   error
+	attrs []slog.Attr
+	// pcs is nil when err already carries a stack
+	pcs []uintptr
+}
+
+// NewError returns an error with msg carrying attrs and the stack of
+// the caller. Error and Fatal log the attrs and that stack instead of
+// the stack of the logging call, so it points where the failure
+// originated.
+//
+//	return glog.NewError("quota exceeded", slog.Int("limit", limit))
+func NewError(msg string, attrs ...slog.Attr) error {
+	// skip runtime.Callers, callers and NewError
+	return &stackError{msg: msg, attrs: attrs, pcs: callers(3)}
+}
+
+// Wrap returns err annotated with msg and attrs like NewError, nil if
+// err is nil. The stack is only captured when no error in the chain
+// of err carries one already.
+//
+//	return glog.Wrap(err, "load config", slog.String("path", path))
+func Wrap(err error, msg string, attrs ...slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &stackError{msg: msg, err: err, attrs: attrs}
+	var inner *stackError
+	if !errors.As(err, &inner) {
+		// skip runtime.Callers, callers and Wrap
+		e.pcs = callers(3)
+	}
+	return e
+}
+
+func (e *stackError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// callers returns the stack skipping skip frames
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, defaultStackDepth)
+	n := runtime.Callers(skip, pcs)
+	return pcs[:n]
+}
+
+// errorDetails returns the attrs and the creation stack of the errors
+// created with NewError or Wrap in chain, outermost attrs first
+func errorDetails(chain []error) (attrs []slog.Attr, pcs []uintptr) {
+	for _, err := range chain {
+		if e, ok := err.(*stackError); ok {
+			attrs = append(attrs, e.attrs...)
+			if e.pcs != nil {
+				pcs = e.pcs
+			}
+		}
+	}
+	return attrs, pcs
+}
@@ -0,0 +1,152 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type eventKey struct{}
+
+// EventBuilder accumulates attributes over the lifetime of a request
+// and emits them as a single wide record, the canonical log line
+// pattern. It is safe for concurrent use and its methods do nothing
+// on a nil builder, so code can call EventFromContext(ctx).Add
+// without checking whether an event is in flight.
+type EventBuilder struct {
+	logger *BaseLogger
+	ctx    context.Context
+	msg    string
+	start  time.Time
+
+	mu    sync.Mutex
+	attrs []slog.Attr
+	index map[string]int
+	level slog.Level
+	err   error
+
+	emitted atomic.Bool
+}
+
+// NewEvent starts a wide event named msg and returns a context carrying
+// it, see EventFromContext
+//
+//	ctx, ev := logger.NewEvent(r.Context(), "http request")
+//	defer ev.Emit()
+//	ev.Add("route", route)
+func (c *BaseLogger) NewEvent(ctx context.Context, msg string) (context.Context, *EventBuilder) {
+	ev := &EventBuilder{
+		logger: c,
+		ctx:    ctx,
+		msg:    msg,
+		start:  time.Now(),
+		index:  map[string]int{},
+		level:  slog.LevelInfo,
+	}
+	return context.WithValue(ctx, eventKey{}, ev), ev
+}
+
+// EventFromContext returns the event started with NewEvent, or nil
+func EventFromContext(ctx context.Context) *EventBuilder {
+	if ctx == nil {
+		return nil
+	}
+	ev, _ := ctx.Value(eventKey{}).(*EventBuilder)
+	return ev
+}
+
+// Add sets key to value, replacing an earlier value for key
+func (e *EventBuilder) Add(key string, value any) *EventBuilder {
+	return e.AddAttrs(slog.Any(key, value))
+}
+
+// AddAttrs sets attrs, replacing earlier attrs with the same keys
+func (e *EventBuilder) AddAttrs(attrs ...slog.Attr) *EventBuilder {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, a := range attrs {
+		if i, ok := e.index[a.Key]; ok {
+			e.attrs[i] = a
+			continue
+		}
+		e.index[a.Key] = len(e.attrs)
+		e.attrs = append(e.attrs, a)
+	}
+	return e
+}
+
+// Inc adds n to the integer counter key, e.g. ev.Inc("db_calls", 1)
+func (e *EventBuilder) Inc(key string, n int64) *EventBuilder {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if i, ok := e.index[key]; ok {
+		if v := e.attrs[i].Value; v.Kind() == slog.KindInt64 {
+			e.attrs[i] = slog.Int64(key, v.Int64()+n)
+		}
+		return e
+	}
+	e.index[key] = len(e.attrs)
+	e.attrs = append(e.attrs, slog.Int64(key, n))
+	return e
+}
+
+// SetLevel raises the level of the event record to level, it never
+// lowers it
+func (e *EventBuilder) SetLevel(level slog.Level) *EventBuilder {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.level = max(e.level, level)
+	return e
+}
+
+// SetError records err and logs the event at Error with err details
+func (e *EventBuilder) SetError(err error) *EventBuilder {
+	if e == nil || err == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+	e.level = max(e.level, slog.LevelError)
+	return e
+}
+
+// Emit logs the event with the accumulated attributes, args and the
+// elapsed time as "duration". Only the first call emits a record.
+func (e *EventBuilder) Emit(args ...any) {
+	if e == nil || !e.emitted.CompareAndSwap(false, true) {
+		return
+	}
+
+	e.mu.Lock()
+	dargs := make([]any, 0, len(e.attrs)+len(args)+2)
+	for _, a := range e.attrs {
+		dargs = append(dargs, a)
+	}
+	level, err := e.level, e.err
+	e.mu.Unlock()
+
+	dargs = append(dargs, args...)
+	dargs = append(dargs, slog.Duration("duration", time.Since(e.start)))
+
+	if err != nil && level >= slog.LevelError {
+		e.logger.logError(e.ctx, slog.LevelError, e.msg, append(dargs, err)...)
+		return
+	}
+	e.logger.log(e.ctx, 0, level, e.msg, dargs...)
+}
@@ -0,0 +1,104 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultFlushTimeout bounds Flush unless set with WithFlushTimeout
+const DefaultFlushTimeout = 5 * time.Second
+
+// Flusher is implemented by handlers and sinks that hold records
+// before writing them, such as AsyncHandler, BufferedWriter,
+// GzipWriter or a *bufio.Writer passed to WithOutput. Flush, Close and
+// Fatal flush every Flusher of the logger tree.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush writes the records held by the handlers and sinks of the tree,
+// waiting at most the flush timeout, see WithFlushTimeout. Fatal
+// flushes before exiting.
+func (c *BaseLogger) Flush() {
+	timeout := c.getRoot().flushTimeout
+	if timeout <= 0 {
+		timeout = DefaultFlushTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_ = c.FlushContext(ctx)
+}
+
+// FlushContext is Flush with the deadline of ctx. Flushers still
+// running when ctx is done are abandoned.
+func (c *BaseLogger) FlushContext(ctx context.Context) error {
+	flushers := c.flushers()
+	done := make(chan error, 1)
+
+	go func() {
+		var errs []error
+		for _, f := range flushers {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("glog: flush: %w", ctx.Err())
+	}
+}
+
+// flushers returns the Flushers of the tree of c, or of c alone when
+// it is not the root, in the order they must be flushed: the async
+// handlers first as they write into the sinks
+func (c *BaseLogger) flushers() []Flusher {
+	var flushers []Flusher
+	for _, h := range c.asyncHandlers() {
+		flushers = append(flushers, h)
+	}
+
+	var writers []io.Writer
+	add := func(w io.Writer) {
+		if w == nil {
+			return
+		}
+		for _, seen := range writers {
+			if sameWriter(seen, w) {
+				return
+			}
+		}
+		writers = append(writers, w)
+	}
+
+	root := c.getRoot()
+	root.mu.RLock()
+	for _, logger := range c.treeLoggers() {
+		add(logger.stdout)
+		add(logger.stderr)
+	}
+	if root == c {
+		add(root.auditOut)
+		for _, closer := range root.closers {
+			if w, ok := closer.(io.Writer); ok {
+				add(w)
+			}
+		}
+	}
+	root.mu.RUnlock()
+
+	for _, w := range writers {
+		if f, ok := w.(Flusher); ok {
+			flushers = append(flushers, f)
+		}
+	}
+	return flushers
+}
@@ -0,0 +1,78 @@
+package glog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// flushWriter records whether it was flushed
+type flushWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushed bool
+}
+
+func (w *flushWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *flushWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushed = true
+	return nil
+}
+
+func (w *flushWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func (w *flushWriter) wasFlushed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushed
+}
+
+func TestChildPanicFlushesTree(t *testing.T) {
+	rootOut, auditOut := &flushWriter{}, &flushWriter{}
+	l := NewLogger(WithOutput(rootOut), WithAuditOutput(auditOut))
+	child := l.GetLogger("child", WithOutput(io.Discard))
+
+	func() {
+		defer func() { recover() }()
+		child.Panic("boom")
+	}()
+
+	if !rootOut.wasFlushed() || !auditOut.wasFlushed() {
+		t.Fatalf("root output flushed %v, audit output flushed %v, want both", rootOut.wasFlushed(), auditOut.wasFlushed())
+	}
+}
+
+func TestChildCloseKeepsTreeRunning(t *testing.T) {
+	out := &flushWriter{}
+	l := NewLogger(WithOutput(out), WithAsync(16))
+	defer l.Close()
+
+	child := l.GetLogger("child")
+	child.Info("from child")
+	if err := child.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("from root after child Close")
+	l.GetLogger("sibling").Info("from sibling after child Close")
+	l.Flush()
+
+	for _, msg := range []string{"from child", "from root after", "from sibling after"} {
+		if got := out.String(); !strings.Contains(got, msg) {
+			t.Errorf("missing %q in output:\n%s", msg, got)
+		}
+	}
+}
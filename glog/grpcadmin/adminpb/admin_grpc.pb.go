@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admin.proto
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LoggerAdmin_ListLoggers_FullMethodName = "/glog.admin.v1.LoggerAdmin/ListLoggers"
+	LoggerAdmin_SetLevel_FullMethodName    = "/glog.admin.v1.LoggerAdmin/SetLevel"
+	LoggerAdmin_Focus_FullMethodName       = "/glog.admin.v1.LoggerAdmin/Focus"
+	LoggerAdmin_Flush_FullMethodName       = "/glog.admin.v1.LoggerAdmin/Flush"
+)
+
+// LoggerAdminClient is the client API for LoggerAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LoggerAdminClient interface {
+	ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error)
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error)
+	Focus(ctx context.Context, in *FocusRequest, opts ...grpc.CallOption) (*FocusResponse, error)
+	Flush(ctx context.Context, in *FlushRequest, opts ...grpc.CallOption) (*FlushResponse, error)
+}
+
+type loggerAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoggerAdminClient(cc grpc.ClientConnInterface) LoggerAdminClient {
+	return &loggerAdminClient{cc}
+}
+
+func (c *loggerAdminClient) ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoggersResponse)
+	err := c.cc.Invoke(ctx, LoggerAdmin_ListLoggers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerAdminClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLevelResponse)
+	err := c.cc.Invoke(ctx, LoggerAdmin_SetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerAdminClient) Focus(ctx context.Context, in *FocusRequest, opts ...grpc.CallOption) (*FocusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FocusResponse)
+	err := c.cc.Invoke(ctx, LoggerAdmin_Focus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerAdminClient) Flush(ctx context.Context, in *FlushRequest, opts ...grpc.CallOption) (*FlushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FlushResponse)
+	err := c.cc.Invoke(ctx, LoggerAdmin_Flush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoggerAdminServer is the server API for LoggerAdmin service.
+// All implementations must embed UnimplementedLoggerAdminServer
+// for forward compatibility.
+type LoggerAdminServer interface {
+	ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error)
+	SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error)
+	Focus(context.Context, *FocusRequest) (*FocusResponse, error)
+	Flush(context.Context, *FlushRequest) (*FlushResponse, error)
+	mustEmbedUnimplementedLoggerAdminServer()
+}
+
+// UnimplementedLoggerAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLoggerAdminServer struct{}
+
+func (UnimplementedLoggerAdminServer) ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoggers not implemented")
+}
+func (UnimplementedLoggerAdminServer) SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLoggerAdminServer) Focus(context.Context, *FocusRequest) (*FocusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Focus not implemented")
+}
+func (UnimplementedLoggerAdminServer) Flush(context.Context, *FlushRequest) (*FlushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Flush not implemented")
+}
+func (UnimplementedLoggerAdminServer) mustEmbedUnimplementedLoggerAdminServer() {}
+func (UnimplementedLoggerAdminServer) testEmbeddedByValue()                     {}
+
+// UnsafeLoggerAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoggerAdminServer will
+// result in compilation errors.
+type UnsafeLoggerAdminServer interface {
+	mustEmbedUnimplementedLoggerAdminServer()
+}
+
+func RegisterLoggerAdminServer(s grpc.ServiceRegistrar, srv LoggerAdminServer) {
+	// If the following call pancis, it indicates UnimplementedLoggerAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LoggerAdmin_ServiceDesc, srv)
+}
+
+func _LoggerAdmin_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerAdminServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoggerAdmin_ListLoggers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerAdminServer).ListLoggers(ctx, req.(*ListLoggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerAdmin_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerAdminServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoggerAdmin_SetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerAdminServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerAdmin_Focus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FocusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerAdminServer).Focus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoggerAdmin_Focus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerAdminServer).Focus(ctx, req.(*FocusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerAdmin_Flush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerAdminServer).Flush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoggerAdmin_Flush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerAdminServer).Flush(ctx, req.(*FlushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LoggerAdmin_ServiceDesc is the grpc.ServiceDesc for LoggerAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoggerAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "glog.admin.v1.LoggerAdmin",
+	HandlerType: (*LoggerAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LoggerAdmin_ListLoggers_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LoggerAdmin_SetLevel_Handler,
+		},
+		{
+			MethodName: "Focus",
+			Handler:    _LoggerAdmin_Focus_Handler,
+		},
+		{
+			MethodName: "Flush",
+			Handler:    _LoggerAdmin_Flush_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,173 @@
+// Package grpcadmin serves the LoggerAdmin gRPC service, see
+// adminpb/admin.proto, so fleet tooling can change levels, focus and
+// flush the loggers of many instances.
+//
+//	srv := grpc.NewServer()
+//	grpcadmin.Register(srv, logger, grpcadmin.WithAuth(grpcadmin.TokenAuth(token)))
+//
+// Calls are denied unless an AuthFunc is set with WithAuth.
+package grpcadmin
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+
+	"github.com/goliatone/go-logger/glog"
+	"github.com/goliatone/go-logger/glog/grpcadmin/adminpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthFunc authorizes a call to method, the full gRPC method name. A
+// returned status error is passed to the client as is, other errors
+// become PermissionDenied.
+type AuthFunc func(ctx context.Context, method string) error
+
+// Option configures the server
+type Option func(*Server)
+
+// WithAuth checks every call with fn before it runs
+func WithAuth(fn AuthFunc) Option {
+	return func(s *Server) {
+		s.auth = fn
+	}
+}
+
+// AllowAll returns an AuthFunc accepting every call, for servers that
+// authenticate callers at the transport, e.g. with mutual TLS
+func AllowAll() AuthFunc {
+	return func(context.Context, string) error {
+		return nil
+	}
+}
+
+// TokenAuth returns an AuthFunc accepting calls whose "authorization"
+// metadata is "Bearer <token>"
+func TokenAuth(token string) AuthFunc {
+	return func(ctx context.Context, method string) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			got, ok := strings.CutPrefix(v, "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid or missing token")
+	}
+}
+
+// Server implements adminpb.LoggerAdminServer
+type Server struct {
+	adminpb.UnimplementedLoggerAdminServer
+	logger *glog.BaseLogger
+	auth   AuthFunc
+}
+
+// NewServer returns a server controlling logger, which should be the
+// root logger. Without WithAuth every call is denied.
+func NewServer(logger *glog.BaseLogger, opts ...Option) *Server {
+	s := &Server{logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register registers a NewServer with srv
+func Register(srv grpc.ServiceRegistrar, logger *glog.BaseLogger, opts ...Option) *Server {
+	s := NewServer(logger, opts...)
+	adminpb.RegisterLoggerAdminServer(srv, s)
+	return s
+}
+
+// ListLoggers implements adminpb.LoggerAdminServer
+func (s *Server) ListLoggers(ctx context.Context, req *adminpb.ListLoggersRequest) (*adminpb.ListLoggersResponse, error) {
+	if err := s.authorize(ctx, adminpb.LoggerAdmin_ListLoggers_FullMethodName); err != nil {
+		return nil, err
+	}
+
+	resp := &adminpb.ListLoggersResponse{
+		Loggers: []*adminpb.Logger{loggerInfo(glog.ControlRoot, s.logger)},
+	}
+	for _, name := range s.logger.LoggerNames() {
+		if logger, ok := s.logger.Lookup(name); ok {
+			resp.Loggers = append(resp.Loggers, loggerInfo(name, logger))
+		}
+	}
+	return resp, nil
+}
+
+// SetLevel implements adminpb.LoggerAdminServer
+func (s *Server) SetLevel(ctx context.Context, req *adminpb.SetLevelRequest) (*adminpb.SetLevelResponse, error) {
+	if err := s.authorize(ctx, adminpb.LoggerAdmin_SetLevel_FullMethodName); err != nil {
+		return nil, err
+	}
+
+	if _, err := glog.ParseLevel(req.GetLevel()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown level %q", req.GetLevel())
+	}
+
+	logger := s.logger
+	if req.GetName() != glog.ControlRoot {
+		var ok bool
+		if logger, ok = s.logger.Lookup(req.GetName()); !ok {
+			return nil, status.Errorf(codes.NotFound, "unknown logger %q", req.GetName())
+		}
+	}
+
+	logger.WithLevel(req.GetLevel())
+	return &adminpb.SetLevelResponse{Logger: loggerInfo(req.GetName(), logger)}, nil
+}
+
+// Focus implements adminpb.LoggerAdminServer
+func (s *Server) Focus(ctx context.Context, req *adminpb.FocusRequest) (*adminpb.FocusResponse, error) {
+	if err := s.authorize(ctx, adminpb.LoggerAdmin_Focus_FullMethodName); err != nil {
+		return nil, err
+	}
+
+	if len(req.GetNames()) == 0 {
+		s.logger.Unfocus()
+	} else {
+		s.logger.Focus(req.GetNames()...)
+	}
+	return &adminpb.FocusResponse{}, nil
+}
+
+// Flush implements adminpb.LoggerAdminServer
+func (s *Server) Flush(ctx context.Context, req *adminpb.FlushRequest) (*adminpb.FlushResponse, error) {
+	if err := s.authorize(ctx, adminpb.LoggerAdmin_Flush_FullMethodName); err != nil {
+		return nil, err
+	}
+
+	s.logger.Flush()
+	return &adminpb.FlushResponse{}, nil
+}
+
+func (s *Server) authorize(ctx context.Context, method string) error {
+	if s.auth == nil {
+		return status.Error(codes.PermissionDenied, "no authorization configured")
+	}
+	err := s.auth(ctx, method)
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+func loggerInfo(name string, logger *glog.BaseLogger) *adminpb.Logger {
+	return &adminpb.Logger{Name: name, Level: levelName(logger.Level())}
+}
+
+func levelName(level slog.Level) string {
+	if name, ok := glog.CustomLevels[level]; ok {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(level.String())
+}
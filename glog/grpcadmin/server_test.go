@@ -0,0 +1,75 @@
+package grpcadmin
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/goliatone/go-logger/glog"
+	"github.com/goliatone/go-logger/glog/grpcadmin/adminpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorize(t *testing.T) {
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"no auth denies", nil, context.Background(), codes.PermissionDenied},
+		{"allow all", []Option{WithAuth(AllowAll())}, context.Background(), codes.OK},
+		{"valid token", []Option{WithAuth(TokenAuth("secret"))}, withToken("secret"), codes.OK},
+		{"invalid token", []Option{WithAuth(TokenAuth("secret"))}, withToken("other"), codes.Unauthenticated},
+		{"missing token", []Option{WithAuth(TokenAuth("secret"))}, context.Background(), codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(glog.NewLogger(glog.WithOutput(io.Discard)), tt.opts...)
+
+			_, err := s.ListLoggers(tt.ctx, &adminpb.ListLoggersRequest{})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("ListLoggers() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logger := glog.NewLogger(glog.WithOutput(io.Discard))
+	logger.GetLogger("db")
+	s := NewServer(logger, WithAuth(AllowAll()))
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		req   *adminpb.SetLevelRequest
+		want  codes.Code
+		level string
+	}{
+		{"root", &adminpb.SetLevelRequest{Name: glog.ControlRoot, Level: "debug"}, codes.OK, "debug"},
+		{"child", &adminpb.SetLevelRequest{Name: "db", Level: "warn"}, codes.OK, "warn"},
+		{"unknown logger", &adminpb.SetLevelRequest{Name: "nope", Level: "warn"}, codes.NotFound, ""},
+		{"unknown level", &adminpb.SetLevelRequest{Name: "db", Level: "loud"}, codes.InvalidArgument, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.SetLevel(ctx, tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("SetLevel() code = %v, want %v", got, tt.want)
+			}
+			if err == nil && resp.GetLogger().GetLevel() != tt.level {
+				t.Fatalf("level = %q, want %q", resp.GetLogger().GetLevel(), tt.level)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BackpressurePolicy decides what AsyncHandler does when its queue is full
+type BackpressurePolicy int
+
+const (
+	// DropNewest discards the record being logged
+	DropNewest BackpressurePolicy = iota
+	// DropOldest discards the oldest queued record to make room
+	DropOldest
+	// Block waits for room in the queue, up to the block timeout if
+	// one is set, and drops the record being logged on timeout
+	Block
+)
+
+// ErrAsyncClosed is returned when logging to a closed AsyncHandler
+var ErrAsyncClosed = errors.New("glog: async handler closed")
+
+type AsyncOption func(*asyncQueue)
+
+// WithAsyncPolicy sets the policy applied when the queue is full,
+// defaults to DropNewest
+func WithAsyncPolicy(policy BackpressurePolicy) AsyncOption {
+	return func(q *asyncQueue) {
+		q.policy = policy
+	}
+}
+
+// WithAsyncBlockTimeout limits how long the Block policy waits for room
+// in the queue, zero waits indefinitely
+func WithAsyncBlockTimeout(timeout time.Duration) AsyncOption {
+	return func(q *asyncQueue) {
+		q.timeout = timeout
+	}
+}
+
+// WithAsyncOnDrop sets a callback invoked with every dropped record,
+// it runs on the goroutine that dropped the record and must not block
+func WithAsyncOnDrop(fn func(r slog.Record)) AsyncOption {
+	return func(q *asyncQueue) {
+		q.onDrop = fn
+	}
+}
+
+// AsyncHandler queues records and passes them to the wrapped handler
+// from a background goroutine, so slow outputs do not block callers.
+// Call Close to drain the queue before exiting.
+type AsyncHandler struct {
+	handler slog.Handler
+	queue   *asyncQueue
+}
+
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	done    chan struct{}
+}
+
+type asyncQueue struct {
+	ch      chan asyncEntry
+	size    int
+	opts    []AsyncOption
+	policy  BackpressurePolicy
+	timeout time.Duration
+	onDrop  func(r slog.Record)
+	drops   *dropStats
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewAsyncHandler(handler slog.Handler, size int, opts ...AsyncOption) *AsyncHandler {
+	q := &asyncQueue{
+		ch:   make(chan asyncEntry, max(size, 1)),
+		size: size,
+		opts: opts,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	q.wg.Add(1)
+	go q.run()
+
+	return &AsyncHandler{
+		handler: handler,
+		queue:   q,
+	}
+}
+
+func (q *asyncQueue) run() {
+	defer q.wg.Done()
+	for e := range q.ch {
+		if e.done != nil {
+			close(e.done)
+			continue
+		}
+		if err := e.handler.Handle(e.ctx, e.record); err != nil && q.drops != nil {
+			q.drops.add(DropReasonSinkError)
+		}
+	}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	// the context is used after Handle returns, keep its values only
+	return h.queue.push(asyncEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		record:  r.Clone(),
+	})
+}
+
+func (q *asyncQueue) push(e asyncEntry) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrAsyncClosed
+	}
+
+	select {
+	case q.ch <- e:
+		return nil
+	default:
+	}
+
+	switch q.policy {
+	case DropOldest:
+		var flushes []asyncEntry
+		for {
+			select {
+			case old := <-q.ch:
+				if old.done != nil {
+					// a Flush waits for the records queued before
+					// it, requeue it instead of releasing it early
+					flushes = append(flushes, old)
+					continue
+				}
+				q.drop(old.record)
+			default:
+			}
+			// requeued behind newer records a Flush only waits longer,
+			// the worker keeps draining so the send completes
+			for _, f := range flushes {
+				q.ch <- f
+			}
+			flushes = flushes[:0]
+
+			select {
+			case q.ch <- e:
+				return nil
+			default:
+			}
+		}
+	case Block:
+		if q.timeout <= 0 {
+			q.ch <- e
+			return nil
+		}
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+		select {
+		case q.ch <- e:
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	q.drop(e.record)
+	return nil
+}
+
+func (q *asyncQueue) drop(r slog.Record) {
+	if q.drops != nil {
+		q.drops.add(DropReasonQueueFull)
+	}
+	if q.onDrop != nil {
+		q.onDrop(r)
+	}
+}
+
+// Flush blocks until the records queued before the call are handled
+func (h *AsyncHandler) Flush() error {
+	q := h.queue
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil
+	}
+	done := make(chan struct{})
+	q.ch <- asyncEntry{done: done}
+	q.mu.RUnlock()
+	<-done
+	return nil
+}
+
+// Close stops accepting records and waits for the queued ones to be handled
+func (h *AsyncHandler) Close() error {
+	q := h.queue
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.ch)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{
+		handler: h.handler.WithAttrs(attrs),
+		queue:   h.queue,
+	}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{
+		handler: h.handler.WithGroup(name),
+		queue:   h.queue,
+	}
+}
@@ -0,0 +1,121 @@
+package glog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateHandler blocks in Handle until the gate is opened
+type gateHandler struct {
+	gate chan struct{}
+
+	mu      sync.Mutex
+	handled []string
+}
+
+func (h *gateHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *gateHandler) Handle(_ context.Context, r slog.Record) error {
+	<-h.gate
+	h.mu.Lock()
+	h.handled = append(h.handled, r.Message)
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *gateHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *gateHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *gateHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.handled...)
+}
+
+func TestAsyncDropOldestKeepsFlush(t *testing.T) {
+	inner := &gateHandler{gate: make(chan struct{})}
+	h := NewAsyncHandler(inner, 2, WithAsyncPolicy(DropOldest))
+	defer h.Close()
+
+	log := func(msg string) {
+		h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0))
+	}
+
+	log("first")
+	// wait for the worker to take the first record and block on it
+	time.Sleep(20 * time.Millisecond)
+
+	flushed := make(chan struct{})
+	go func() {
+		h.Flush()
+		close(flushed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// overflow the queue so DropOldest evicts entries
+	for _, msg := range []string{"a", "b", "c"} {
+		log(msg)
+	}
+
+	select {
+	case <-flushed:
+		close(inner.gate)
+		t.Fatal("Flush returned before the first record was handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.gate)
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("Flush did not return")
+	}
+
+	if got := inner.messages(); len(got) == 0 || got[0] != "first" {
+		t.Fatalf("handled %v, want first record handled before Flush returned", got)
+	}
+}
+
+func TestAsyncQueueSurvivesRebuild(t *testing.T) {
+	l := NewLogger(WithOutput(io.Discard), WithAsync(16))
+	defer l.Close()
+
+	queue := l.async.queue
+	before := runtime.NumGoroutine()
+
+	types := []string{LoggerTypeJSON, LoggerTypeConsole, LoggerTypePretty}
+	for i := 0; i < 30; i++ {
+		l.WithLoggerType(types[i%len(types)])
+		l.Info("x")
+	}
+
+	if l.async.queue != queue {
+		t.Fatal("rebuilding the handler replaced the async queue")
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("goroutines grew from %d to %d", before, after)
+	}
+}
+
+func TestAsyncChildWithOwnQueue(t *testing.T) {
+	l := NewLogger(WithOutput(io.Discard), WithAsync(16))
+	child := l.GetLogger("db", WithAsync(4))
+
+	if child.async.queue == l.async.queue {
+		t.Fatal("child with another async config shares the parent queue")
+	}
+
+	child.Info("x")
+	l.Info("x")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !child.async.queue.closed || !l.async.queue.closed {
+		t.Fatal("Close left an async queue open")
+	}
+}
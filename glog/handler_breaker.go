@@ -0,0 +1,143 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BreakerHandler opens a circuit when more than threshold records at
+// Error or above are handled within window. While the circuit is open,
+// for cooldown, records below Error are dropped so that a logger stuck
+// in an error loop does not flood its sinks. Opening and closing the
+// circuit are reported with a single warning record each.
+type BreakerHandler struct {
+	handler slog.Handler
+	state   *breakerState
+}
+
+type breakerState struct {
+	name      string
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	drops     *dropStats
+
+	// openUntil is the unix nano time the circuit closes, zero when closed
+	openUntil atomic.Int64
+	dropped   atomic.Uint64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	errors      int
+}
+
+func NewBreakerHandler(handler slog.Handler, name string, threshold int, window, cooldown time.Duration) slog.Handler {
+	return newBreakerHandler(handler, newBreakerState(name, threshold, window, cooldown, nil))
+}
+
+func newBreakerState(name string, threshold int, window, cooldown time.Duration, drops *dropStats) *breakerState {
+	return &breakerState{
+		name:      name,
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		drops:     drops,
+	}
+}
+
+func newBreakerHandler(handler slog.Handler, state *breakerState) slog.Handler {
+	return &BreakerHandler{handler: handler, state: state}
+}
+
+// Enabled does not consult the circuit so that suppressed records
+// are counted in Handle
+func (h *BreakerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *BreakerHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	now := time.Now()
+
+	if until := s.openUntil.Load(); until != 0 && now.UnixNano() >= until {
+		if s.openUntil.CompareAndSwap(until, 0) {
+			h.report(ctx, now, "circuit closed", slog.Uint64("suppressed", s.dropped.Swap(0)))
+		}
+	}
+
+	if r.Level < slog.LevelError {
+		if s.isOpen(now) {
+			s.dropped.Add(1)
+			if s.drops != nil {
+				s.drops.add(DropReasonCircuitOpen)
+			}
+			return nil
+		}
+		return h.handler.Handle(ctx, r)
+	}
+
+	if err := h.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	if s.countError(now) {
+		h.report(ctx, now, "circuit opened",
+			slog.Int("threshold", s.threshold),
+			slog.Duration("window", s.window),
+			slog.Duration("cooldown", s.cooldown),
+		)
+	}
+	return nil
+}
+
+func (s *breakerState) isOpen(now time.Time) bool {
+	until := s.openUntil.Load()
+	return until != 0 && now.UnixNano() < until
+}
+
+// countError records an error and reports whether it opened the circuit
+func (s *breakerState) countError(now time.Time) bool {
+	if s.isOpen(now) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.windowStart) >= s.window {
+		s.windowStart = now
+		s.errors = 0
+	}
+	s.errors++
+
+	if s.errors <= s.threshold {
+		return false
+	}
+
+	s.errors = 0
+	s.openUntil.Store(now.Add(s.cooldown).UnixNano())
+	return true
+}
+
+func (h *BreakerHandler) report(ctx context.Context, now time.Time, msg string, attrs ...slog.Attr) {
+	r := slog.NewRecord(now, slog.LevelWarn, "logger "+h.state.name+" "+msg, 0)
+	r.AddAttrs(attrs...)
+	_ = h.handler.Handle(ctx, r)
+}
+
+func (h *BreakerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BreakerHandler{
+		handler: h.handler.WithAttrs(attrs),
+		state:   h.state,
+	}
+}
+
+func (h *BreakerHandler) WithGroup(name string) slog.Handler {
+	return &BreakerHandler{
+		handler: h.handler.WithGroup(name),
+		state:   h.state,
+	}
+}
@@ -0,0 +1,32 @@
+package glog
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithErrorBreakerValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		window    time.Duration
+		cooldown  time.Duration
+		wantErr   bool
+	}{
+		{"valid", 3, time.Second, time.Minute, false},
+		{"zero threshold", 0, time.Second, time.Minute, true},
+		{"negative threshold", -1, time.Second, time.Minute, true},
+		{"zero window", 3, 0, time.Minute, true},
+		{"negative cooldown", 3, time.Second, -time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoggerE(WithOutput(io.Discard), WithErrorBreaker(tt.threshold, tt.window, tt.cooldown))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("NewLoggerE() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,18 +2,37 @@ package glog
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
+	"reflect"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 )
 
 var ColorConsoleTSFormat = "2006-01-02 15:04:05.000"
 
+// LevelIcons maps levels to the glyphs rendered before the message
+// when icons are enabled with WithColorConsoleIcons
+var LevelIcons = map[slog.Level]string{
+	LevelTrace:      "·",
+	slog.LevelDebug: "•",
+	slog.LevelInfo:  "ℹ",
+	slog.LevelWarn:  "⚠",
+	slog.LevelError: "✖",
+	LevelDPanic:     "✖",
+	LevelPanic:      "✖",
+	LevelFatal:      "✖",
+}
+
 var (
 	maxDisplayNameLenMu sync.Mutex
 	maxDisplayNameLen   = 6
@@ -28,6 +47,90 @@ func WithColorConsoleTSFormat(format string) ColorConsoleOption {
 	}
 }
 
+// WithColorConsoleLocation renders timestamps in loc, a nil loc
+// keeps the record's time zone
+func WithColorConsoleLocation(loc *time.Location) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.location = loc
+	}
+}
+
+// WithColorConsoleTheme sets the palette used to render records
+func WithColorConsoleTheme(theme Theme) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.theme = theme
+	}
+}
+
+// WithColorConsoleColorMode sets whether colors are emitted,
+// defaults to ColorAuto
+func WithColorConsoleColorMode(mode ColorMode) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.colorMode = mode
+	}
+}
+
+// WithColorConsoleIcons renders a level glyph before each message
+func WithColorConsoleIcons(enabled bool) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.icons = enabled
+	}
+}
+
+// WithColorConsoleNameWidth renders logger names in a fixed width
+// column, longer names are shortened with an ellipsis
+func WithColorConsoleNameWidth(width int) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.nameWidth = width
+	}
+}
+
+// WithColorConsoleLevelWidth sets the width of the level column
+func WithColorConsoleLevelWidth(width int) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.levelWidth = width
+	}
+}
+
+// WithColorConsoleLineWidth wraps attributes that would make a line
+// longer than width onto indented continuation lines
+func WithColorConsoleLineWidth(width int) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.lineWidth = width
+	}
+}
+
+// WithColorConsoleSourceRight right aligns source info to the line
+// width, it has no effect unless a line width is set
+func WithColorConsoleSourceRight(enabled bool) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.sourceRight = enabled
+	}
+}
+
+// WithColorConsoleLevelBadges renders levels as badges using the
+// theme's background colors
+func WithColorConsoleLevelBadges(enabled bool) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.badges = enabled
+	}
+}
+
+// WithColorConsoleMultiline renders nested values (maps, structs, slices
+// and errors) on indented continuation lines instead of inline
+func WithColorConsoleMultiline(enabled bool) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.multiline = enabled
+	}
+}
+
+// WithColorConsoleIndent sets the indentation used for continuation lines
+func WithColorConsoleIndent(indent string) ColorConsoleOption {
+	return func(cch *ColorConsoleHandler) {
+		cch.indent = indent
+	}
+}
+
 // ColorConsoleHandler is a custom slog.Handler that outputs colored logs to the console
 type ColorConsoleHandler struct {
 	out      io.Writer
@@ -36,22 +139,54 @@ type ColorConsoleHandler struct {
 	attrs    []slog.Attr
 	groups   []string
 	tsFormat string
+	location *time.Location
+
+	theme     Theme
+	colorMode ColorMode
+	icons     bool
+	badges    bool
+	multiline bool
+	indent    string
+
+	nameWidth   int
+	levelWidth  int
+	lineWidth   int
+	sourceRight bool
+
+	// styles caches escape sequences per color, guarded by mu
+	styles map[*color.Color]ansiStyle
 }
 
 // NewColorConsoleHandler creates a new ColorConsoleHandler with the provided options
-func NewColorConsoleHandler(out io.Writer, opts *slog.HandlerOptions) slog.Handler {
+func NewColorConsoleHandler(out io.Writer, opts *slog.HandlerOptions, options ...ColorConsoleOption) slog.Handler {
 	if opts == nil {
 		opts = &slog.HandlerOptions{}
 	}
 
-	return &ColorConsoleHandler{
-		out:      out,
-		opts:     opts,
-		mu:       &sync.Mutex{},
-		attrs:    []slog.Attr{},
-		groups:   []string{},
-		tsFormat: ColorConsoleTSFormat,
+	h := &ColorConsoleHandler{
+		out:        out,
+		opts:       opts,
+		mu:         &sync.Mutex{},
+		attrs:      []slog.Attr{},
+		groups:     []string{},
+		tsFormat:   ColorConsoleTSFormat,
+		theme:      DarkTheme(),
+		indent:     "    ",
+		levelWidth: 6,
+		styles:     map[*color.Color]ansiStyle{},
 	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	colored := shouldColor(h.colorMode, out)
+	if colored {
+		h.out = prepareColorOutput(out)
+	}
+	h.theme = h.theme.clone(colored)
+
+	return h
 }
 
 func (h *ColorConsoleHandler) WithTSFormat(format string) *ColorConsoleHandler {
@@ -61,7 +196,55 @@ func (h *ColorConsoleHandler) WithTSFormat(format string) *ColorConsoleHandler {
 
 // Enabled implements slog.Handler.
 func (h *ColorConsoleHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.opts.Level.Level()
+	min := slog.LevelInfo
+	if h.opts.Level != nil {
+		min = h.opts.Level.Level()
+	}
+	return level >= min
+}
+
+// consoleAttr is an attribute collected while rendering a record
+type consoleAttr struct {
+	key   string
+	value any
+}
+
+// consoleState holds the scratch space used to render a record
+type consoleState struct {
+	buf   []byte
+	attrs []consoleAttr
+}
+
+// maxPooledBuffer keeps unusually large records from pinning memory
+const maxPooledBuffer = 64 << 10
+
+var consoleStatePool = sync.Pool{
+	New: func() any {
+		return &consoleState{buf: make([]byte, 0, 1024)}
+	},
+}
+
+// set stores v under key, replacing an earlier value with the same key
+func (s *consoleState) set(key string, v any) {
+	for i := range s.attrs {
+		if s.attrs[i].key == key {
+			s.attrs[i].value = v
+			return
+		}
+	}
+	s.attrs = append(s.attrs, consoleAttr{key: key, value: v})
+}
+
+// take removes key and returns its value
+func (s *consoleState) take(key string) (any, bool) {
+	for i := range s.attrs {
+		if s.attrs[i].key == key {
+			v := s.attrs[i].value
+			s.attrs = slices.Delete(s.attrs, i, i+1)
+			return v, true
+		}
+	}
+	return nil, false
 }
 
 // Handle implements slog.Handler.
@@ -69,81 +252,276 @@ func (h *ColorConsoleHandler) Handle(ctx context.Context, r slog.Record) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	coloredLevel := h.colorizeLevel(r.Level)
+	state := consoleStatePool.Get().(*consoleState)
+	defer func() {
+		if cap(state.buf) <= maxPooledBuffer {
+			clear(state.attrs)
+			state.buf, state.attrs = state.buf[:0], state.attrs[:0]
+			consoleStatePool.Put(state)
+		}
+	}()
 
-	ts := r.Time.Format(h.tsFormat)
-	coloredTs := color.New(color.FgHiBlack).Sprint(ts)
+	// attrs added with WithAttrs are wrapped in their groups, so
+	// ReplaceAttr sees the same group path as for record attrs
+	for _, attr := range h.attrs {
+		h.collectAttr(state, nil, attr, true)
+	}
 
-	msg := r.Message
-	coloredMsg := color.WhiteString(msg)
+	r.Attrs(func(a slog.Attr) bool {
+		h.collectAttr(state, h.groups, a, true)
+		return true
+	})
 
-	attrMap := make(map[string]any)
+	var loggerName string
+	if name, ok := state.take(LoggerKey); ok {
+		if s, isString := name.(string); isString {
+			loggerName = s
+		} else {
+			state.set(LoggerKey, name)
+		}
+	}
 
-	for _, attr := range h.attrs {
-		attrMap[attr.Key] = attr.Value.Any()
+	var source any
+	if h.opts.AddSource {
+		source, _ = state.take("source")
+	}
+	if source == nil {
+		if src := h.recordSource(r); src != "" {
+			source = src
+		}
 	}
 
-	r.Attrs(func(a slog.Attr) bool {
-		if h.opts.ReplaceAttr != nil {
-			a = h.opts.ReplaceAttr(h.groups, a)
+	stack, _ := state.take(StackKey)
+
+	state.take("ts")
+	state.take("time")
+	state.take("level")
+
+	var nested []consoleAttr
+	if h.multiline {
+		state.attrs, nested = h.splitNested(state.attrs)
+	}
+
+	buf := state.buf
+	if loggerName != "" {
+		buf = h.appendLoggerName(buf, loggerName)
+	}
+	buf = append(buf, ' ')
+	buf = h.appendTime(buf, r.Time)
+	buf = append(buf, ' ')
+	buf = h.appendLevel(buf, r.Level)
+	buf = h.appendLevelIcon(buf, r.Level)
+	buf = h.styleStart(buf, h.theme.Message)
+	buf = appendSanitized(buf, r.Message)
+	buf = h.styleEnd(buf, h.theme.Message)
+
+	if h.lineWidth > 0 {
+		header := string(buf)
+		var sourceInfo string
+		if source != nil {
+			sourceInfo = string(h.appendSource(nil, source))
+		}
+		buf = append(buf[:0], h.layoutLine(header, h.attrParts(state.attrs), sourceInfo)...)
+	} else {
+		buf = append(buf, ' ')
+		for _, a := range state.attrs {
+			buf = h.appendAttr(buf, a)
+		}
+		buf = append(buf, ' ')
+		if source != nil {
+			buf = h.appendSource(buf, source)
 		}
+		buf = append(buf, '\n')
+	}
 
-		if a.Equal(slog.Attr{}) {
-			return true
+	if len(nested) > 0 {
+		buf = h.appendNested(buf, nested)
+	}
+
+	if stack != nil {
+		buf = h.styleStart(buf, h.theme.Stack)
+		buf = fmt.Appendf(buf, "%s", stack)
+		buf = h.styleEnd(buf, h.theme.Stack)
+	}
+
+	state.buf = buf
+	_, err := h.out.Write(buf)
+	return err
+}
+
+// collectAttr stores a in state under its dotted group path. Groups are
+// flattened so their members render as "group.key=value", empty groups
+// are dropped and groups without a key are inlined like slog does.
+func (h *ColorConsoleHandler) collectAttr(state *consoleState, groups []string, a slog.Attr, replace bool) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		if len(members) == 0 {
+			return
 		}
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, m := range members {
+			h.collectAttr(state, groups, m, replace)
+		}
+		return
+	}
 
-		key := a.Key
-		if len(h.groups) > 0 {
-			key = strings.Join(append(slices.Clone(h.groups), key), ".")
+	if replace && h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			h.collectAttr(state, groups, a, false)
+			return
 		}
+	}
 
-		attrMap[key] = a.Value.Any()
-		return true
-	})
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+
+	state.set(key, a.Value.Any())
+}
+
+// ansiStyle holds the escape sequences that open and close a color
+type ansiStyle struct {
+	start string
+	end   string
+}
+
+// style returns the escape sequences for c, they are resolved once per
+// color and cached since rendering through color.Sprint allocates
+func (h *ColorConsoleHandler) style(c *color.Color) ansiStyle {
+	if c == nil {
+		return ansiStyle{}
+	}
+
+	if st, ok := h.styles[c]; ok {
+		return st
+	}
+
+	start, end, _ := strings.Cut(c.Sprint("\x00"), "\x00")
+	st := ansiStyle{start: start, end: end}
+	h.styles[c] = st
+	return st
+}
 
-	var loggerInfo string
-	if loggerName, ok := attrMap["logger"].(string); ok {
-		loggerInfo = h.formatLoggerName(loggerName)
-		delete(attrMap, "logger") // remove key from attributes to avoid duplication
+func (h *ColorConsoleHandler) styleStart(buf []byte, c *color.Color) []byte {
+	return append(buf, h.style(c).start...)
+}
+
+func (h *ColorConsoleHandler) styleEnd(buf []byte, c *color.Color) []byte {
+	return append(buf, h.style(c).end...)
+}
+
+// appendPaint appends s rendered in color c
+func (h *ColorConsoleHandler) appendPaint(buf []byte, c *color.Color, s string) []byte {
+	st := h.style(c)
+	buf = append(buf, st.start...)
+	buf = append(buf, s...)
+	return append(buf, st.end...)
+}
+
+func (h *ColorConsoleHandler) appendTime(buf []byte, t time.Time) []byte {
+	if h.location != nil {
+		t = t.In(h.location)
 	}
+	buf = h.styleStart(buf, h.theme.Timestamp)
+	buf = t.AppendFormat(buf, h.tsFormat)
+	return h.styleEnd(buf, h.theme.Timestamp)
+}
 
-	var sourceInfo string
-	if source, ok := attrMap["source"]; ok && h.opts.AddSource {
-		sourceInfo = color.New(color.FgHiBlack).Sprintf("(%s)", source)
-		delete(attrMap, "source")
+func (h *ColorConsoleHandler) appendSource(buf []byte, source any) []byte {
+	buf = h.styleStart(buf, h.theme.Source)
+	buf = append(buf, '(')
+	if s, ok := source.(string); ok {
+		buf = append(buf, s...)
+	} else {
+		buf = fmt.Appendf(buf, "%s", source)
 	}
+	buf = append(buf, ')')
+	return h.styleEnd(buf, h.theme.Source)
+}
+
+// appendAttr appends a as " key=value"
+func (h *ColorConsoleHandler) appendAttr(buf []byte, a consoleAttr) []byte {
+	buf = append(buf, ' ')
+	if a.key == ErrorKey {
+		buf = h.appendPaint(buf, h.theme.ErrorKey, "message")
+	} else {
+		buf = h.styleStart(buf, h.theme.Key)
+		buf = appendSanitized(buf, a.key)
+		buf = h.styleEnd(buf, h.theme.Key)
+	}
+	buf = append(buf, '=')
+	return appendConsoleValue(buf, a.value)
+}
 
-	var stackInfo string
-	if err, ok := attrMap["stack"]; ok {
-		stackInfo = color.New(color.FgHiBlack).Sprintf("%s", err)
-		delete(attrMap, "stack")
+// appendConsoleValue appends v as formatted by %v, common types
+// are appended directly to avoid going through fmt. Line breaks and
+// invalid UTF-8 are escaped to keep the record on one line.
+func appendConsoleValue(buf []byte, v any) []byte {
+	switch x := v.(type) {
+	case string:
+		return appendSanitized(buf, x)
+	case int64:
+		return strconv.AppendInt(buf, x, 10)
+	case int:
+		return strconv.AppendInt(buf, int64(x), 10)
+	case uint64:
+		return strconv.AppendUint(buf, x, 10)
+	case float64:
+		return strconv.AppendFloat(buf, x, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(buf, x)
+	case time.Duration:
+		return append(buf, x.String()...)
+	default:
+		start := len(buf)
+		buf = fmt.Appendf(buf, "%v", v)
+		if needsSanitize(buf[start:]) {
+			return appendSanitized(buf[:start], string(buf[start:]))
+		}
+		return buf
+	}
+}
 
+// recordSource resolves the record's call site and passes it
+// through ReplaceAttr so source formatting options apply
+func (h *ColorConsoleHandler) recordSource(r slog.Record) string {
+	if !h.opts.AddSource || r.PC == 0 {
+		return ""
 	}
 
-	delete(attrMap, "ts")
-	delete(attrMap, "time")
-	delete(attrMap, "level")
+	frames := runtime.CallersFrames([]uintptr{r.PC})
+	frame, _ := frames.Next()
 
-	var formattedAttrs string
-	if len(attrMap) > 0 {
-		formattedAttrs = h.formatAttrs(attrMap)
+	a := slog.Any(slog.SourceKey, &slog.Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	})
+
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(nil, a)
 	}
 
-	// TODO: can we use a template here?
-	fmt.Fprintf(h.out, "%s %s %s%s %s %s\n",
-		loggerInfo,
-		coloredTs,
-		coloredLevel,
-		coloredMsg,
-		formattedAttrs,
-		sourceInfo,
-	)
+	if a.Equal(slog.Attr{}) {
+		return ""
+	}
 
-	if stackInfo != "" {
-		fmt.Fprintf(h.out, "%s", stackInfo)
+	if src, ok := a.Value.Any().(*slog.Source); ok {
+		return fmt.Sprintf("%s:%d", src.File, src.Line)
 	}
 
-	return nil
+	return a.Value.String()
 }
 
 func (h *ColorConsoleHandler) updateMaxNameLen(name string) {
@@ -159,7 +537,11 @@ func (h *ColorConsoleHandler) updateMaxNameLen(name string) {
 	}
 }
 
-func (h *ColorConsoleHandler) formatLoggerName(name string) string {
+func (h *ColorConsoleHandler) appendLoggerName(buf []byte, name string) []byte {
+	if h.nameWidth > 0 {
+		return append(buf, h.formatFixedLoggerName(name)...)
+	}
+
 	h.updateMaxNameLen(name)
 
 	dislayName := name
@@ -167,20 +549,32 @@ func (h *ColorConsoleHandler) formatLoggerName(name string) string {
 		dislayName = name[:maxAllowedNameLen-3] + "..."
 	}
 
-	withBrackets := "[" + dislayName + "]"
-
 	maxDisplayNameLenMu.Lock()
 	currentMaxLen := maxDisplayNameLen
 	maxDisplayNameLenMu.Unlock()
 
-	// create dyanmic template for display len
-	formatStr := fmt.Sprintf("%%%ds", currentMaxLen+2)
-
-	return color.New(color.FgGreen, color.Bold).Sprintf(formatStr, withBrackets)
+	// right align the bracketed name to the widest name seen so far
+	buf = h.styleStart(buf, h.theme.LoggerName)
+	for pad := currentMaxLen - utf8.RuneCountInString(dislayName); pad > 0; pad-- {
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, '[')
+	buf = append(buf, dislayName...)
+	buf = append(buf, ']')
+	return h.styleEnd(buf, h.theme.LoggerName)
 }
 
 // WithAttrs implements slog.Handler.
 func (h *ColorConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	// nest attrs in the open groups, innermost first
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+
 	h2 := *h
 	h2.attrs = append(slices.Clone(h.attrs), attrs...)
 	return &h2
@@ -193,8 +587,8 @@ func (h *ColorConsoleHandler) WithGroup(name string) slog.Handler {
 	return &h2
 }
 
-// colorizeLevel returns the level string with appropriate color
-func (h *ColorConsoleHandler) colorizeLevel(level slog.Level) string {
+// appendLevel appends the level name padded to the level column
+func (h *ColorConsoleHandler) appendLevel(buf []byte, level slog.Level) []byte {
 	levelName := level.String()
 
 	// Check for custom level names
@@ -204,42 +598,190 @@ func (h *ColorConsoleHandler) colorizeLevel(level slog.Level) string {
 
 	// Make it uppercase and pad it for alignment
 	levelName = strings.ToUpper(levelName)
-	levelName = fmt.Sprintf("%-6s", levelName)
 
-	// Apply color based on level
+	pad := h.levelWidth - len(levelName)
+	if h.badges {
+		buf = h.styleStart(buf, h.levelBadgeColor(level))
+		buf = append(buf, ' ')
+		buf = append(buf, levelName...)
+		buf = append(buf, ' ')
+		buf = h.styleEnd(buf, h.levelBadgeColor(level))
+		return appendSpaces(buf, max(pad, 1))
+	}
+
+	buf = h.styleStart(buf, h.levelColor(level))
+	buf = append(buf, levelName...)
+	buf = appendSpaces(buf, pad)
+	return h.styleEnd(buf, h.levelColor(level))
+}
+
+func appendSpaces(buf []byte, n int) []byte {
+	for ; n > 0; n-- {
+		buf = append(buf, ' ')
+	}
+	return buf
+}
+
+// levelColor returns the theme color for level, nil for unknown levels
+func (h *ColorConsoleHandler) levelColor(level slog.Level) *color.Color {
 	switch {
 	case level == LevelTrace:
-		return color.New(color.FgHiBlack).Sprint(levelName)
+		return h.theme.Trace
 	case level == slog.LevelDebug:
-		return color.New(color.FgMagenta).Sprint(levelName)
+		return h.theme.Debug
 	case level == slog.LevelInfo:
-		return color.New(color.FgBlue).Sprint(levelName)
+		return h.theme.Info
 	case level == slog.LevelWarn:
-		return color.New(color.FgYellow).Sprint(levelName)
-	case level == slog.LevelError:
-		return color.New(color.FgRed, color.Bold).Sprint(levelName)
+		return h.theme.Warn
+	case level == slog.LevelError, level == LevelDPanic:
+		return h.theme.Error
+	case level == LevelPanic, level == LevelFatal:
+		return h.theme.Fatal
 	default:
-		return levelName
+		return nil
 	}
 }
 
-// formatAttrs formats a map of attributes into a string
-func (h *ColorConsoleHandler) formatAttrs(attrs map[string]any) string {
-	if len(attrs) == 0 {
-		return ""
+// levelBadgeColor returns the theme badge color for level
+func (h *ColorConsoleHandler) levelBadgeColor(level slog.Level) *color.Color {
+	switch {
+	case level == LevelTrace:
+		return h.theme.TraceBadge
+	case level == slog.LevelDebug:
+		return h.theme.DebugBadge
+	case level == slog.LevelInfo:
+		return h.theme.InfoBadge
+	case level == slog.LevelWarn:
+		return h.theme.WarnBadge
+	case level == slog.LevelError, level == LevelDPanic:
+		return h.theme.ErrorBadge
+	case level == LevelPanic, level == LevelFatal:
+		return h.theme.FatalBadge
+	default:
+		return nil
+	}
+}
+
+// appendLevelIcon appends the colored glyph for level followed by a
+// space, nothing is appended if icons are disabled or level has no glyph
+func (h *ColorConsoleHandler) appendLevelIcon(buf []byte, level slog.Level) []byte {
+	if !h.icons {
+		return buf
+	}
+
+	icon, ok := LevelIcons[level]
+	if !ok {
+		return buf
+	}
+
+	buf = h.appendPaint(buf, h.levelColor(level), icon)
+	return append(buf, ' ')
+}
+
+// attrParts formats each attribute as a " key=value" string
+func (h *ColorConsoleHandler) attrParts(attrs []consoleAttr) []string {
+	parts := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		parts = append(parts, string(h.appendAttr(nil, a)))
 	}
 
-	var key string
-	var parts []string
-	for k, v := range attrs {
-		if k == "error" {
-			key = color.New(color.FgHiRed).Sprint("message")
+	return parts
+}
+
+// splitNested separates nested values that are rendered on
+// continuation lines, sorted by key for stable output
+func (h *ColorConsoleHandler) splitNested(attrs []consoleAttr) (inline, nested []consoleAttr) {
+	inline = attrs[:0]
+	for _, a := range attrs {
+		if isNestedValue(a.value) {
+			nested = append(nested, a)
 		} else {
-			key = color.New(color.FgHiYellow).Sprint(k)
+			inline = append(inline, a)
 		}
-		val := fmt.Sprintf("%v", v)
-		parts = append(parts, fmt.Sprintf(" %s=%s", key, val))
 	}
 
-	return strings.Join(parts, "")
+	slices.SortFunc(nested, func(a, b consoleAttr) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return inline, nested
+}
+
+// appendNested renders nested values as indented blocks, one per key
+func (h *ColorConsoleHandler) appendNested(buf []byte, nested []consoleAttr) []byte {
+	for _, a := range nested {
+		buf = append(buf, h.indent...)
+		if a.key == ErrorKey {
+			buf = h.appendPaint(buf, h.theme.ErrorKey, "message")
+		} else {
+			buf = h.appendPaint(buf, h.theme.Key, a.key)
+		}
+		buf = append(buf, ":\n"...)
+
+		for _, line := range strings.Split(formatNestedValue(a.value, h.indent), "\n") {
+			buf = append(buf, h.indent...)
+			buf = append(buf, h.indent...)
+			buf = append(buf, line...)
+			buf = append(buf, '\n')
+		}
+	}
+
+	return buf
+}
+
+// isNestedValue reports whether v should be rendered on continuation lines
+func isNestedValue(v any) bool {
+	if v == nil {
+		return false
+	}
+
+	if _, ok := v.(error); ok {
+		return true
+	}
+
+	if _, ok := v.(fmt.Stringer); ok {
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map, reflect.Struct:
+		return rv.Kind() != reflect.Map || rv.Len() > 0
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			return false
+		}
+		elem := rv.Type().Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		switch elem.Kind() {
+		case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array, reflect.Interface:
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatNestedValue renders v as indented text, errors are
+// rendered using their message
+func formatNestedValue(v any, indent string) string {
+	if err, ok := v.(error); ok {
+		return strings.TrimRight(err.Error(), "\n")
+	}
+
+	b, err := json.MarshalIndent(v, "", indent)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	return string(b)
 }
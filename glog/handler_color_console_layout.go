@@ -0,0 +1,75 @@
+package glog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleLen returns the number of runes in s ignoring ANSI escapes
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// formatFixedLoggerName renders name in a column of h.nameWidth
+func (h *ColorConsoleHandler) formatFixedLoggerName(name string) string {
+	displayName := name
+	if utf8.RuneCountInString(name) > h.nameWidth {
+		runes := []rune(name)
+		if h.nameWidth > 3 {
+			displayName = string(runes[:h.nameWidth-3]) + "..."
+		} else {
+			displayName = string(runes[:h.nameWidth])
+		}
+	}
+
+	withBrackets := "[" + displayName + "]"
+
+	return paint(h.theme.LoggerName, fmt.Sprintf("%*s", h.nameWidth+2, withBrackets))
+}
+
+// layoutLine joins header, attribute parts and source info, wrapping
+// parts that would overflow h.lineWidth onto indented lines
+func (h *ColorConsoleHandler) layoutLine(header string, parts []string, source string) string {
+	var lines []string
+
+	cur := header
+	curLen := visibleLen(header)
+
+	for _, part := range parts {
+		partLen := visibleLen(part)
+		if curLen+partLen > h.lineWidth && curLen > len(h.indent) {
+			lines = append(lines, cur)
+			part = strings.TrimPrefix(part, " ")
+			cur = h.indent + part
+			curLen = len(h.indent) + partLen - 1
+			continue
+		}
+		cur += part
+		curLen += partLen
+	}
+
+	if source != "" {
+		sourceLen := visibleLen(source)
+		switch {
+		case !h.sourceRight:
+			cur += " " + source
+		case curLen+1+sourceLen <= h.lineWidth:
+			cur += strings.Repeat(" ", h.lineWidth-curLen-sourceLen) + source
+		default:
+			lines = append(lines, cur)
+			pad := h.lineWidth - sourceLen
+			if pad < 0 {
+				pad = 0
+			}
+			cur = strings.Repeat(" ", pad) + source
+		}
+	}
+
+	lines = append(lines, cur)
+
+	return strings.Join(lines, "\n") + "\n"
+}
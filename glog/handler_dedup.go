@@ -0,0 +1,91 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// DedupMode selects which value is kept when a key repeats
+type DedupMode int
+
+const (
+	// DedupLastWins keeps the value added last, e.g. a call site arg
+	// over a With attr
+	DedupLastWins DedupMode = iota
+	// DedupFirstWins keeps the value added first
+	DedupFirstWins
+)
+
+// DedupHandler removes repeated attribute keys across WithAttrs calls
+// and record attrs before passing records to the wrapped handler. Keys
+// are compared within the current group, attrs added before a WithGroup
+// call are deduplicated and passed on when the group is opened.
+type DedupHandler struct {
+	handler slog.Handler
+	mode    DedupMode
+	attrs   []slog.Attr
+}
+
+func NewDedupHandler(handler slog.Handler, mode DedupMode) slog.Handler {
+	return &DedupHandler{
+		handler: handler,
+		mode:    mode,
+	}
+}
+
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(dedupAttrs(attrs, h.mode)...)
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(slices.Clip(h.attrs), attrs...)
+	return &h2
+}
+
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	handler := h.handler
+	if len(h.attrs) > 0 {
+		handler = handler.WithAttrs(dedupAttrs(h.attrs, h.mode))
+	}
+	return &DedupHandler{
+		handler: handler.WithGroup(name),
+		mode:    h.mode,
+	}
+}
+
+// dedupAttrs returns attrs with one attr per key, kept at the position
+// of the first occurrence. Attrs without a key are left alone.
+func dedupAttrs(attrs []slog.Attr, mode DedupMode) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	seen := make(map[string]int, len(attrs))
+	for _, a := range attrs {
+		if a.Key == "" {
+			out = append(out, a)
+			continue
+		}
+		if i, ok := seen[a.Key]; ok {
+			if mode == DedupLastWins {
+				out[i] = a
+			}
+			continue
+		}
+		seen[a.Key] = len(out)
+		out = append(out, a)
+	}
+	return out
+}
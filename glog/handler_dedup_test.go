@@ -0,0 +1,83 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestDedupHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		mode DedupMode
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "last wins",
+			mode: DedupLastWins,
+			log:  func(l *slog.Logger) { l.With("a", 1).Info("x", "a", 2) },
+			want: `{"a":2}`,
+		},
+		{
+			name: "first wins",
+			mode: DedupFirstWins,
+			log:  func(l *slog.Logger) { l.With("a", 1).Info("x", "a", 2) },
+			want: `{"a":1}`,
+		},
+		{
+			name: "record attrs",
+			mode: DedupLastWins,
+			log:  func(l *slog.Logger) { l.Info("x", "a", 1, "b", 2, "a", 3) },
+			want: `{"a":3,"b":2}`,
+		},
+		{
+			name: "position of first occurrence",
+			mode: DedupLastWins,
+			log:  func(l *slog.Logger) { l.With("a", 1, "b", 2).Info("x", "a", 3) },
+			want: `{"a":3,"b":2}`,
+		},
+		{
+			name: "groups are separate",
+			mode: DedupLastWins,
+			log:  func(l *slog.Logger) { l.With("a", 1).WithGroup("g").Info("x", "a", 2, "a", 3) },
+			want: `{"a":1,"g":{"a":3}}`,
+		},
+		{
+			name: "attrs before group",
+			mode: DedupFirstWins,
+			log:  func(l *slog.Logger) { l.With("a", 1).With("a", 2).WithGroup("g").Info("x", "b", 1) },
+			want: `{"a":1,"g":{"b":1}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			json := slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropBuiltins})
+			tt.log(slog.New(NewDedupHandler(json, tt.mode)))
+
+			if got := attrsJSON(t, buf.Bytes()); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// dropBuiltins removes time, level and message so tests compare attrs
+func dropBuiltins(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// attrsJSON returns the JSON record line with keys in encoding order
+func attrsJSON(t *testing.T, line []byte) string {
+	t.Helper()
+	if !json.Valid(line) {
+		t.Fatalf("invalid JSON %q", line)
+	}
+	return string(bytes.TrimSpace(line))
+}
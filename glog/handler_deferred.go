@@ -0,0 +1,186 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// DefaultDeferredSize is the number of records a DeferredBuffer keeps
+// unless set otherwise
+const DefaultDeferredSize = 256
+
+type deferredKey struct{}
+
+// DeferredBuffer holds the Debug and Trace records of a context until
+// it is flushed or discarded, e.g. to keep the debug records of a
+// request only when it fails or is slow. An Error record logged with
+// the context flushes the buffer first, so the debug records precede
+// it and later ones are written directly.
+type DeferredBuffer struct {
+	mu      sync.Mutex
+	records []deferredRecord
+	next    int
+	full    bool
+	dropped int
+	state   deferredState
+}
+
+type deferredState int
+
+const (
+	deferredBuffering deferredState = iota
+	deferredFlushed
+	deferredDiscarded
+)
+
+type deferredRecord struct {
+	handler slog.Handler
+	ctx     context.Context
+	r       slog.Record
+}
+
+// ContextWithDeferred returns a copy of ctx whose records below Info
+// are held in the returned buffer, at most size of them, dropping the
+// oldest. The context level is lowered to Trace, see ContextWithLevel.
+//
+//	ctx, buf := glog.ContextWithDeferred(r.Context(), 0)
+//	defer func() {
+//		if failed || time.Since(start) > time.Second {
+//			buf.Flush()
+//			return
+//		}
+//		buf.Discard()
+//	}()
+func ContextWithDeferred(ctx context.Context, size int) (context.Context, *DeferredBuffer) {
+	if size <= 0 {
+		size = DefaultDeferredSize
+	}
+	buf := &DeferredBuffer{records: make([]deferredRecord, size)}
+	ctx = ContextWithLevel(ctx, LevelTrace)
+	return context.WithValue(ctx, deferredKey{}, buf), buf
+}
+
+// DeferredFromContext returns the buffer set with ContextWithDeferred
+func DeferredFromContext(ctx context.Context) *DeferredBuffer {
+	if ctx == nil {
+		return nil
+	}
+	buf, _ := ctx.Value(deferredKey{}).(*DeferredBuffer)
+	return buf
+}
+
+// Flush writes the held records in order, records logged afterwards
+// are written directly
+func (b *DeferredBuffer) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush()
+}
+
+// Discard drops the held records and the ones logged afterwards
+func (b *DeferredBuffer) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != deferredBuffering {
+		return
+	}
+	b.state = deferredDiscarded
+	b.records = nil
+}
+
+// Dropped returns the number of records dropped because the buffer was
+// full
+func (b *DeferredBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.dropped
+}
+
+func (b *DeferredBuffer) flush() error {
+	if b.state != deferredBuffering {
+		return nil
+	}
+	b.state = deferredFlushed
+
+	var errs []error
+	emit := func(records []deferredRecord) {
+		for _, rec := range records {
+			if err := rec.handler.Handle(rec.ctx, rec.r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if b.full {
+		emit(b.records[b.next:])
+	}
+	emit(b.records[:b.next])
+
+	b.records = nil
+	return errors.Join(errs...)
+}
+
+// hold keeps the record unless the buffer was flushed, it reports
+// whether the record must be written now
+func (b *DeferredBuffer) hold(h slog.Handler, ctx context.Context, r slog.Record) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case deferredFlushed:
+		return true
+	case deferredDiscarded:
+		return false
+	}
+
+	if b.full {
+		b.dropped++
+	}
+	b.records[b.next] = deferredRecord{handler: h, ctx: ctx, r: r.Clone()}
+	b.next++
+	if b.next == len(b.records) {
+		b.next = 0
+		b.full = true
+	}
+	return false
+}
+
+// DeferredHandler holds records below Info in the DeferredBuffer of
+// their context and flushes it when an Error record arrives. Records
+// of contexts without a buffer pass through.
+type DeferredHandler struct {
+	handler slog.Handler
+}
+
+func NewDeferredHandler(handler slog.Handler) slog.Handler {
+	return &DeferredHandler{handler: handler}
+}
+
+func (h *DeferredHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *DeferredHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo {
+		if buf := DeferredFromContext(ctx); buf != nil && !buf.hold(h.handler, ctx, r) {
+			return nil
+		}
+	} else if r.Level >= slog.LevelError {
+		if buf := DeferredFromContext(ctx); buf != nil {
+			buf.Flush()
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *DeferredHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DeferredHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *DeferredHandler) WithGroup(name string) slog.Handler {
+	return &DeferredHandler{handler: h.handler.WithGroup(name)}
+}
@@ -0,0 +1,58 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Enricher returns attributes computed at log time for record r
+type Enricher func(ctx context.Context, r slog.Record) []slog.Attr
+
+// EnrichHandler adds the attributes returned by its enrichers
+// to every record before passing it to the wrapped handler
+type EnrichHandler struct {
+	handler   slog.Handler
+	enrichers []Enricher
+}
+
+func NewEnrichHandler(handler slog.Handler, enrichers ...Enricher) slog.Handler {
+	return &EnrichHandler{
+		handler:   handler,
+		enrichers: enrichers,
+	}
+}
+
+func (h *EnrichHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *EnrichHandler) Handle(ctx context.Context, r slog.Record) error {
+	cloned := false
+	for _, enrich := range h.enrichers {
+		attrs := enrich(ctx, r)
+		if len(attrs) == 0 {
+			continue
+		}
+		// only copy records that gain attributes
+		if !cloned {
+			r = r.Clone()
+			cloned = true
+		}
+		r.AddAttrs(attrs...)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *EnrichHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &EnrichHandler{
+		handler:   h.handler.WithAttrs(attrs),
+		enrichers: h.enrichers,
+	}
+}
+
+func (h *EnrichHandler) WithGroup(name string) slog.Handler {
+	return &EnrichHandler{
+		handler:   h.handler.WithGroup(name),
+		enrichers: h.enrichers,
+	}
+}
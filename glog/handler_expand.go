@@ -0,0 +1,152 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// ExpandHandler rewrites dotted keys into groups before passing records
+// to the wrapped handler, e.g. "http.method" and "http.status" become
+// an "http" group with "method" and "status", for consumers that prefer
+// nested JSON objects. Attrs added with WithAttrs are held until a record
+// is handled so that they merge with the record attrs.
+type ExpandHandler struct {
+	handler slog.Handler
+	attrs   []slog.Attr
+}
+
+func NewExpandHandler(handler slog.Handler) slog.Handler {
+	return &ExpandHandler{handler: handler}
+}
+
+func (h *ExpandHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *ExpandHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(expandAttrs(attrs)...)
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *ExpandHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ExpandHandler{
+		handler: h.handler,
+		attrs:   append(slices.Clip(h.attrs), attrs...),
+	}
+}
+
+func (h *ExpandHandler) WithGroup(name string) slog.Handler {
+	handler := h.handler
+	if len(h.attrs) > 0 {
+		handler = handler.WithAttrs(expandAttrs(h.attrs))
+	}
+	return &ExpandHandler{handler: handler.WithGroup(name)}
+}
+
+// expandNode is an attr being built, either a leaf or a group
+type expandNode struct {
+	attr     slog.Attr
+	children []*expandNode
+	group    bool
+}
+
+// child returns the group child named key, creating it if missing.
+// It returns nil if key is already used by a leaf.
+func (n *expandNode) child(key string) *expandNode {
+	for _, c := range n.children {
+		if c.attr.Key == key {
+			if !c.group {
+				return nil
+			}
+			return c
+		}
+	}
+	c := &expandNode{attr: slog.Attr{Key: key}, group: true}
+	n.children = append(n.children, c)
+	return c
+}
+
+// add inserts a under n, splitting its key on dots
+func (n *expandNode) add(a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		target := n
+		if a.Key != "" {
+			target = n.path(a.Key)
+		}
+		if target == nil {
+			n.children = append(n.children, &expandNode{attr: a})
+			return
+		}
+		for _, ga := range a.Value.Group() {
+			target.add(ga)
+		}
+		return
+	}
+
+	head, key := "", a.Key
+	if i := strings.LastIndexByte(a.Key, '.'); i > 0 && i < len(a.Key)-1 {
+		head, key = a.Key[:i], a.Key[i+1:]
+	}
+
+	target := n
+	if head != "" {
+		target = n.path(head)
+	}
+	if target == nil {
+		// the path is taken by a leaf, keep the dotted key
+		n.children = append(n.children, &expandNode{attr: a})
+		return
+	}
+
+	a.Key = key
+	target.children = append(target.children, &expandNode{attr: a})
+}
+
+// path returns the group node for a dotted path, or nil if a part
+// of it is empty or already used by a leaf
+func (n *expandNode) path(path string) *expandNode {
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return nil
+		}
+		if n = n.child(part); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// attrs returns the children of n as attrs
+func (n *expandNode) attrs() []slog.Attr {
+	out := make([]slog.Attr, 0, len(n.children))
+	for _, c := range n.children {
+		if c.group {
+			out = append(out, slog.Attr{Key: c.attr.Key, Value: slog.GroupValue(c.attrs()...)})
+			continue
+		}
+		out = append(out, c.attr)
+	}
+	return out
+}
+
+// expandAttrs turns dotted keys in attrs into nested groups, merging
+// attrs that share a prefix
+func expandAttrs(attrs []slog.Attr) []slog.Attr {
+	root := &expandNode{group: true}
+	for _, a := range attrs {
+		root.add(a)
+	}
+	return root.attrs()
+}
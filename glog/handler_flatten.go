@@ -0,0 +1,71 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// FlattenHandler rewrites groups into dotted keys before passing records
+// to the wrapped handler, e.g. a "method" attr in the "http" group is
+// passed as "http.method", for consumers that cannot handle nested
+// objects in JSON output
+type FlattenHandler struct {
+	handler slog.Handler
+	prefix  string
+}
+
+func NewFlattenHandler(handler slog.Handler) slog.Handler {
+	return &FlattenHandler{handler: handler}
+}
+
+func (h *FlattenHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *FlattenHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(flattenAttr(nil, h.prefix, a)...)
+		return true
+	})
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *FlattenHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var flat []slog.Attr
+	for _, a := range attrs {
+		flat = flattenAttr(flat, h.prefix, a)
+	}
+	return &FlattenHandler{
+		handler: h.handler.WithAttrs(flat),
+		prefix:  h.prefix,
+	}
+}
+
+func (h *FlattenHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &FlattenHandler{
+		handler: h.handler,
+		prefix:  h.prefix + name + ".",
+	}
+}
+
+// flattenAttr appends a to dst with group members as prefixed keys,
+// empty groups are dropped and groups without a key are inlined
+func flattenAttr(dst []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		a.Key = prefix + a.Key
+		return append(dst, a)
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		dst = flattenAttr(dst, prefix, ga)
+	}
+	return dst
+}
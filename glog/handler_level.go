@@ -0,0 +1,62 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelHandler drops records below level, which can be changed at
+// runtime when it is a *slog.LevelVar, or below the level of their
+// context when it is lower, see ContextWithLevel and
+// WithSampledSpanLevel
+type LevelHandler struct {
+	level   slog.Leveler
+	handler slog.Handler
+	sampled *sampledSpanLevel
+}
+
+// sampledSpanLevel is the level of contexts with a sampled span
+type sampledSpanLevel struct {
+	level   slog.Level
+	extract SpanExtractor
+}
+
+func NewLevelHandler(level slog.Leveler, handler slog.Handler) slog.Handler {
+	return &LevelHandler{
+		level:   level,
+		handler: handler,
+	}
+}
+
+func (h *LevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	min := h.level.Level()
+	if ctxLevel, ok := LevelFromContext(ctx); ok && ctxLevel < min {
+		min = ctxLevel
+	}
+	if h.sampled != nil && h.sampled.level < min && ctx != nil {
+		if span, ok := h.sampled.extract(ctx); ok && span.Sampled {
+			min = h.sampled.level
+		}
+	}
+	return level >= min && h.handler.Enabled(ctx, level)
+}
+
+func (h *LevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelHandler{
+		level:   h.level,
+		handler: h.handler.WithAttrs(attrs),
+		sampled: h.sampled,
+	}
+}
+
+func (h *LevelHandler) WithGroup(name string) slog.Handler {
+	return &LevelHandler{
+		level:   h.level,
+		handler: h.handler.WithGroup(name),
+		sampled: h.sampled,
+	}
+}
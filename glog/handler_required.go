@@ -0,0 +1,128 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// RequiredAttrsMode controls what happens when a record is missing
+// required attributes
+type RequiredAttrsMode int
+
+const (
+	// RequiredAttrsWarn emits a warning record alongside the original
+	RequiredAttrsWarn RequiredAttrsMode = iota
+	// RequiredAttrsPanic panics, meant for development and tests
+	RequiredAttrsPanic
+)
+
+// RequiredAttrsHandler checks that records at or above level carry all
+// the required keys, either as record attributes or through WithAttrs.
+// Keys inside groups are matched by their dotted path, e.g. http.method.
+type RequiredAttrsHandler struct {
+	handler  slog.Handler
+	level    slog.Level
+	required []string
+	mode     RequiredAttrsMode
+	prefix   string
+	present  map[string]bool
+}
+
+func NewRequiredAttrsHandler(handler slog.Handler, level slog.Level, mode RequiredAttrsMode, keys ...string) slog.Handler {
+	return &RequiredAttrsHandler{
+		handler:  handler,
+		level:    level,
+		required: keys,
+		mode:     mode,
+		present:  map[string]bool{},
+	}
+}
+
+func (h *RequiredAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RequiredAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level {
+		return h.handler.Handle(ctx, r)
+	}
+
+	var missing []string
+	for _, key := range h.required {
+		if h.present[key] {
+			continue
+		}
+
+		found := false
+		r.Attrs(func(a slog.Attr) bool {
+			found = attrHasPath(a, h.prefix, key)
+			return !found
+		})
+
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) == 0 {
+		return h.handler.Handle(ctx, r)
+	}
+
+	if h.mode == RequiredAttrsPanic {
+		panic(fmt.Sprintf("glog: record %q missing required attrs: %s", r.Message, strings.Join(missing, ", ")))
+	}
+
+	if err := h.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	warn := slog.NewRecord(r.Time, slog.LevelWarn, "record missing required attrs", r.PC)
+	warn.AddAttrs(
+		slog.String("record_msg", r.Message),
+		slog.Any("missing", missing),
+	)
+	return h.handler.Handle(ctx, warn)
+}
+
+func (h *RequiredAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithAttrs(attrs)
+	h2.present = make(map[string]bool, len(h.present)+len(attrs))
+	for k := range h.present {
+		h2.present[k] = true
+	}
+	for _, key := range h.required {
+		for _, a := range attrs {
+			if attrHasPath(a, h.prefix, key) {
+				h2.present[key] = true
+			}
+		}
+	}
+	return &h2
+}
+
+func (h *RequiredAttrsHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithGroup(name)
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// attrHasPath reports whether a, nested under prefix, is or contains path
+func attrHasPath(a slog.Attr, prefix, path string) bool {
+	full := prefix + a.Key
+	if full == path {
+		return true
+	}
+
+	if a.Value.Kind() != slog.KindGroup || !strings.HasPrefix(path, full+".") {
+		return false
+	}
+
+	return slices.ContainsFunc(a.Value.Group(), func(ga slog.Attr) bool {
+		return attrHasPath(ga, full+".", path)
+	})
+}
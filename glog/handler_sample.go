@@ -0,0 +1,185 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SampledKey groups the per level counts of the sampling summary record
+const SampledKey = "sampled"
+
+// SampleHandler keeps a fraction of the records of each configured
+// level, levels without a rate are always kept. Sampling is
+// deterministic, a rate of 0.1 keeps one record out of ten. A rate of
+// zero disables the level and its records are not counted.
+//
+// When interval is positive a summary record with the number of records
+// sampled out per level is emitted once interval has elapsed, on the
+// next record handled.
+type SampleHandler struct {
+	handler slog.Handler
+	state   *sampleState
+}
+
+// sampleState is shared by a logger, its copies and its tenants, so
+// rates replaced with SetSampling apply to all of them and counters
+// continue across changes
+type sampleState struct {
+	rates    atomic.Pointer[sampleRates]
+	interval time.Duration
+	drops    *dropStats
+
+	mu        sync.Mutex
+	lastFlush time.Time
+}
+
+// sampleRates is a snapshot of the rates and their counters, it is
+// replaced as a whole when the rates change
+type sampleRates struct {
+	rates   map[slog.Level]float64
+	seen    map[slog.Level]*atomic.Uint64
+	dropped map[slog.Level]*atomic.Uint64
+}
+
+func NewSampleHandler(handler slog.Handler, rates map[slog.Level]float64, interval time.Duration) slog.Handler {
+	return &SampleHandler{
+		handler: handler,
+		state:   newSampleState(rates, interval, nil),
+	}
+}
+
+func newSampleState(rates map[slog.Level]float64, interval time.Duration, drops *dropStats) *sampleState {
+	s := &sampleState{
+		interval:  interval,
+		drops:     drops,
+		lastFlush: time.Now(),
+	}
+	s.set(rates)
+	return s
+}
+
+// set replaces the rates. Levels that keep a rate keep their counters
+// and records sampled out but not yet summarized are still reported.
+func (s *sampleState) set(rates map[slog.Level]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := &sampleRates{
+		rates:   maps.Clone(rates),
+		seen:    map[slog.Level]*atomic.Uint64{},
+		dropped: map[slog.Level]*atomic.Uint64{},
+	}
+	prev := s.rates.Load()
+	if prev != nil {
+		maps.Copy(next.dropped, prev.dropped)
+	}
+	for level := range rates {
+		if prev != nil && prev.seen[level] != nil {
+			next.seen[level] = prev.seen[level]
+		} else {
+			next.seen[level] = &atomic.Uint64{}
+		}
+		if next.dropped[level] == nil {
+			next.dropped[level] = &atomic.Uint64{}
+		}
+	}
+	s.rates.Store(next)
+}
+
+func (h *SampleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if rate, ok := h.state.rates.Load().rates[level]; ok && rate <= 0 {
+		return false
+	}
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.flush(ctx)
+
+	rs := h.state.rates.Load()
+	if !rs.keep(r.Level) {
+		rs.dropped[r.Level].Add(1)
+		if h.state.drops != nil {
+			h.state.drops.add(DropReasonSampled)
+		}
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// keep reports whether the nth record of level is within the rate,
+// that is whether n*rate crossed an integer boundary, so the first
+// record of each level is always kept
+func (s *sampleRates) keep(level slog.Level) bool {
+	rate, ok := s.rates[level]
+	if !ok || rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	n := s.seen[level].Add(1)
+	return math.Ceil(float64(n)*rate) > math.Ceil(float64(n-1)*rate)
+}
+
+// flush emits the summary record if the interval elapsed and
+// records were sampled out since the last summary. The drop report
+// includes sampled records when enabled and replaces the summary.
+func (h *SampleHandler) flush(ctx context.Context) {
+	s := h.state
+	if s.interval <= 0 || (s.drops != nil && s.drops.interval > 0) {
+		return
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastFlush) < s.interval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastFlush = now
+
+	var counts []slog.Attr
+	for level, dropped := range s.rates.Load().dropped {
+		if n := dropped.Swap(0); n > 0 {
+			counts = append(counts, slog.Uint64(levelLabel(level), n))
+		}
+	}
+	s.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	r := slog.NewRecord(now, slog.LevelInfo, "records sampled", 0)
+	r.AddAttrs(slog.Any(SampledKey, slog.GroupValue(counts...)))
+	_ = h.handler.Handle(ctx, r)
+}
+
+func (h *SampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SampleHandler{
+		handler: h.handler.WithAttrs(attrs),
+		state:   h.state,
+	}
+}
+
+func (h *SampleHandler) WithGroup(name string) slog.Handler {
+	return &SampleHandler{
+		handler: h.handler.WithGroup(name),
+		state:   h.state,
+	}
+}
+
+// levelLabel returns the lower case name of level
+func levelLabel(level slog.Level) string {
+	if name, ok := CustomLevels[level]; ok {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(level.String())
+}
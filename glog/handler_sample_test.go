@@ -0,0 +1,103 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countHandler counts the records it handles
+type countHandler struct {
+	n int
+}
+
+func (h *countHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *countHandler) Handle(context.Context, slog.Record) error {
+	h.n++
+	return nil
+}
+
+func (h *countHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *countHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestSampleHandlerRates(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates map[slog.Level]float64
+		level slog.Level
+		want  int
+	}{
+		{"no rate keeps all", map[slog.Level]float64{slog.LevelDebug: 0.1}, slog.LevelInfo, 100},
+		{"tenth", map[slog.Level]float64{slog.LevelInfo: 0.1}, slog.LevelInfo, 10},
+		{"half", map[slog.Level]float64{slog.LevelInfo: 0.5}, slog.LevelInfo, 50},
+		{"one", map[slog.Level]float64{slog.LevelInfo: 1}, slog.LevelInfo, 100},
+		{"zero", map[slog.Level]float64{slog.LevelInfo: 0}, slog.LevelInfo, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countHandler{}
+			h := NewSampleHandler(inner, tt.rates, 0)
+			for i := 0; i < 100; i++ {
+				if h.Enabled(context.Background(), tt.level) {
+					h.Handle(context.Background(), slog.NewRecord(time.Now(), tt.level, "x", 0))
+				}
+			}
+			if inner.n != tt.want {
+				t.Fatalf("kept %d records, want %d", inner.n, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSamplingReachesTree(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf))
+	child := l.GetLogger("child")
+
+	loggers := map[string]*BaseLogger{
+		"root":          l,
+		"copy":          l.With("copy", true),
+		"context copy":  l.WithContext(context.Background()).(*BaseLogger),
+		"tenant":        l.ForTenant("acme"),
+		"child":         child,
+		"child tenant":  child.ForTenant("acme"),
+		"child of copy": l.With("k", "v").GetLogger("child"),
+	}
+
+	l.SetSampling(map[slog.Level]float64{slog.LevelInfo: 0})
+
+	for name, logger := range loggers {
+		logger.Info("sampled " + name)
+	}
+	if strings.Contains(buf.String(), "sampled") {
+		t.Fatalf("records kept after sampling was disabled for Info:\n%s", buf.String())
+	}
+
+	l.SetSampling(nil)
+	for name, logger := range loggers {
+		logger.Info("kept " + name)
+	}
+	if got := strings.Count(buf.String(), "kept"); got != len(loggers) {
+		t.Fatalf("kept %d records after sampling was removed, want %d", got, len(loggers))
+	}
+}
+
+func TestSetSamplingKeepsCounters(t *testing.T) {
+	var buf bytes.Buffer
+	rates := map[slog.Level]float64{slog.LevelInfo: 0.5}
+	l := NewLogger(WithOutput(&buf), WithSampling(rates, 0))
+
+	// the first record of a level is kept, the second dropped
+	l.Info("first")
+	l.SetSampling(rates)
+	l.Info("second")
+
+	if strings.Contains(buf.String(), "second") {
+		t.Fatal("SetSampling reset the sampling counters")
+	}
+}
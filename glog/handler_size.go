@@ -0,0 +1,114 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+const (
+	// RecordTruncatedKey is added to records that exceeded the attr
+	// count or size limits, with the number of attrs dropped
+	RecordTruncatedKey = "record_truncated"
+
+	// attrOverhead approximates the quoting and separators around
+	// each encoded attr
+	attrOverhead = 6
+)
+
+// SizeGuardHandler caps the number of attrs and the approximate encoded
+// size of records before passing them to the wrapped handler, so that
+// sinks with datagram limits such as GELF or syslog over UDP do not
+// receive oversized records. Attrs are dropped from the end and the
+// message is cut as a last resort. A limit of zero or less disables it.
+type SizeGuardHandler struct {
+	handler  slog.Handler
+	maxAttrs int
+	maxSize  int
+
+	// attrs and size account for the attrs added with WithAttrs,
+	// which cannot be dropped
+	attrs int
+	size  int
+}
+
+func NewSizeGuardHandler(handler slog.Handler, maxAttrs, maxSize int) slog.Handler {
+	return &SizeGuardHandler{
+		handler:  handler,
+		maxAttrs: maxAttrs,
+		maxSize:  maxSize,
+	}
+}
+
+func (h *SizeGuardHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SizeGuardHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	keep := len(attrs)
+	if h.maxAttrs > 0 {
+		keep = min(keep, max(h.maxAttrs-h.attrs, 0))
+	}
+
+	msg := r.Message
+	if h.maxSize > 0 {
+		size := h.size + len(msg)
+		for i, a := range attrs[:keep] {
+			size += attrSize(a)
+			if size > h.maxSize {
+				keep = i
+				break
+			}
+		}
+
+		if over := h.size + len(msg) - h.maxSize; over > 0 {
+			msg, _ = truncateString(msg, max(len(msg)-over, 0))
+		}
+	}
+
+	if keep == len(attrs) && msg == r.Message {
+		return h.handler.Handle(ctx, r)
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	nr.AddAttrs(attrs[:keep]...)
+	nr.AddAttrs(slog.Int(RecordTruncatedKey, len(attrs)-keep))
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *SizeGuardHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithAttrs(attrs)
+	h2.attrs += len(attrs)
+	for _, a := range attrs {
+		h2.size += attrSize(a)
+	}
+	return &h2
+}
+
+func (h *SizeGuardHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithGroup(name)
+	h2.size += len(name) + attrOverhead
+	return &h2
+}
+
+// attrSize approximates the encoded size of a
+func attrSize(a slog.Attr) int {
+	size := len(a.Key) + attrOverhead
+
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			size += attrSize(ga)
+		}
+		return size
+	}
+
+	return size + len(v.String())
+}
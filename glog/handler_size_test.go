@@ -0,0 +1,78 @@
+package glog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSizeGuardHandler(t *testing.T) {
+	long := strings.Repeat("x", 100)
+
+	tests := []struct {
+		name     string
+		maxAttrs int
+		maxSize  int
+		log      func(l *slog.Logger)
+		want     string
+	}{
+		{
+			name:     "within limits",
+			maxAttrs: 3,
+			maxSize:  1000,
+			log:      func(l *slog.Logger) { l.Info("m", "a", 1, "b", 2) },
+			want:     `{"msg":"m","a":1,"b":2}`,
+		},
+		{
+			name:     "too many attrs",
+			maxAttrs: 2,
+			log:      func(l *slog.Logger) { l.Info("m", "a", 1, "b", 2, "c", 3) },
+			want:     `{"msg":"m","a":1,"b":2,"record_truncated":1}`,
+		},
+		{
+			name:     "With attrs count",
+			maxAttrs: 2,
+			log:      func(l *slog.Logger) { l.With("w", 0).Info("m", "a", 1, "b", 2) },
+			want:     `{"msg":"m","w":0,"a":1,"record_truncated":1}`,
+		},
+		{
+			name:    "too large",
+			maxSize: 40,
+			log:     func(l *slog.Logger) { l.Info("m", "a", 1, "b", long) },
+			want:    `{"msg":"m","a":1,"record_truncated":1}`,
+		},
+		{
+			name:    "message cut",
+			maxSize: 10,
+			log:     func(l *slog.Logger) { l.Info(long, "a", 1) },
+			want:    `{"msg":"xxxxxxxxxx...","record_truncated":1}`,
+		},
+		{
+			name: "disabled",
+			log:  func(l *slog.Logger) { l.Info("m", "b", long) },
+			want: `{"msg":"m","b":"` + long + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			json := slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: keepMessage})
+			tt.log(slog.New(NewSizeGuardHandler(json, tt.maxAttrs, tt.maxSize)))
+
+			if got := attrsJSON(t, buf.Bytes()); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// keepMessage removes time and level, the size guard may change the
+// message
+func keepMessage(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.MessageKey {
+		return a
+	}
+	return dropBuiltins(groups, a)
+}
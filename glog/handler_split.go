@@ -0,0 +1,52 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SplitHandler sends records below level to low and the others to
+// high, e.g. to write errors to a different stream
+type SplitHandler struct {
+	level slog.Level
+	low   slog.Handler
+	high  slog.Handler
+}
+
+func NewSplitHandler(level slog.Level, low, high slog.Handler) slog.Handler {
+	return &SplitHandler{
+		level: level,
+		low:   low,
+		high:  high,
+	}
+}
+
+func (h *SplitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.level {
+		return h.low.Enabled(ctx, level)
+	}
+	return h.high.Enabled(ctx, level)
+}
+
+func (h *SplitHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level {
+		return h.low.Handle(ctx, r)
+	}
+	return h.high.Handle(ctx, r)
+}
+
+func (h *SplitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SplitHandler{
+		level: h.level,
+		low:   h.low.WithAttrs(attrs),
+		high:  h.high.WithAttrs(attrs),
+	}
+}
+
+func (h *SplitHandler) WithGroup(name string) slog.Handler {
+	return &SplitHandler{
+		level: h.level,
+		low:   h.low.WithGroup(name),
+		high:  h.high.WithGroup(name),
+	}
+}
@@ -0,0 +1,73 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// StreamHandler passes records to the wrapped handler and, while clients
+// are connected, publishes their JSON encoding to a LogStream
+type StreamHandler struct {
+	handler slog.Handler
+	encoder slog.Handler
+	buf     *streamBuffer
+	stream  *LogStream
+}
+
+// streamBuffer collects the output of a single encoder call, it is
+// shared by all handlers derived from the same StreamHandler
+type streamBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *streamBuffer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+func NewStreamHandler(handler slog.Handler, stream *LogStream, opts *slog.HandlerOptions) slog.Handler {
+	buf := &streamBuffer{}
+	return &StreamHandler{
+		handler: handler,
+		encoder: slog.NewJSONHandler(buf, opts),
+		buf:     buf,
+		stream:  stream,
+	}
+}
+
+func (h *StreamHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *StreamHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.stream.active() {
+		h.buf.mu.Lock()
+		h.buf.buf.Reset()
+		if err := h.encoder.Handle(ctx, r); err == nil {
+			line := bytes.TrimRight(h.buf.buf.Bytes(), "\n")
+			h.stream.publish(r.Level, bytes.Clone(line))
+		}
+		h.buf.mu.Unlock()
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *StreamHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &StreamHandler{
+		handler: h.handler.WithAttrs(attrs),
+		encoder: h.encoder.WithAttrs(attrs),
+		buf:     h.buf,
+		stream:  h.stream,
+	}
+}
+
+func (h *StreamHandler) WithGroup(name string) slog.Handler {
+	return &StreamHandler{
+		handler: h.handler.WithGroup(name),
+		encoder: h.encoder.WithGroup(name),
+		buf:     h.buf,
+		stream:  h.stream,
+	}
+}
@@ -0,0 +1,97 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// SubscribeHandler passes records to the wrapped handler and delivers
+// a resolved copy to the subscribers of a logger tree and to the
+// recent records buffer of the logger
+type SubscribeHandler struct {
+	handler slog.Handler
+	subs    *subscriptions
+	recent  *recentBuffer
+	logger  string
+	attrs   []slog.Attr
+	groups  []string
+	open    [][]slog.Attr
+}
+
+func newSubscribeHandler(handler slog.Handler, subs *subscriptions, recent *recentBuffer, logger string) slog.Handler {
+	return &SubscribeHandler{
+		handler: handler,
+		subs:    subs,
+		recent:  recent,
+		logger:  logger,
+	}
+}
+
+func (h *SubscribeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SubscribeHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.recent != nil || h.subs.active() {
+		rec := h.resolve(r)
+		if h.recent != nil {
+			h.recent.add(rec)
+		}
+		h.subs.publish(rec)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// resolve folds the record attributes into the open groups
+func (h *SubscribeHandler) resolve(r slog.Record) Record {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		inner := append(slices.Clone(h.open[i]), attrs...)
+		attrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(inner...)}}
+	}
+
+	return Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Logger:  h.logger,
+		Source:  recordSourceFromPC(r.PC),
+		Attrs:   append(slices.Clone(h.attrs), attrs...),
+	}
+}
+
+func (h *SubscribeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := *h
+	out.handler = h.handler.WithAttrs(attrs)
+
+	if len(h.groups) == 0 {
+		// the logger name is reported in Record.Logger
+		attrs = slices.DeleteFunc(slices.Clone(attrs), func(a slog.Attr) bool {
+			return a.Key == LoggerKey && a.Value.String() == h.logger
+		})
+		out.attrs = append(slices.Clone(h.attrs), attrs...)
+		return &out
+	}
+
+	last := len(h.groups) - 1
+	out.open = slices.Clone(h.open)
+	out.open[last] = append(slices.Clone(h.open[last]), attrs...)
+	return &out
+}
+
+func (h *SubscribeHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	out := *h
+	out.handler = h.handler.WithGroup(name)
+	out.groups = append(slices.Clone(h.groups), name)
+	out.open = append(slices.Clone(h.open), nil)
+	return &out
+}
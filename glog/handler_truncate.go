@@ -0,0 +1,154 @@
+package glog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+const (
+	// TruncatedKey is added to records where the message or any attr
+	// value was shortened
+	TruncatedKey = "truncated"
+	truncateMark = "..."
+)
+
+// TruncateHandler shortens messages and attr values that exceed the
+// configured limits before passing records to the wrapped handler.
+// A limit of zero or less disables truncation for that field.
+type TruncateHandler struct {
+	handler     slog.Handler
+	maxMsgLen   int
+	maxValueLen int
+	truncated   bool
+}
+
+// NewTruncateHandler wraps handler so messages longer than maxMsgLen
+// and attr values longer than maxValueLen are cut with an ellipsis
+func NewTruncateHandler(handler slog.Handler, maxMsgLen, maxValueLen int) slog.Handler {
+	return &TruncateHandler{
+		handler:     handler,
+		maxMsgLen:   maxMsgLen,
+		maxValueLen: maxValueLen,
+	}
+}
+
+func (h *TruncateHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *TruncateHandler) Handle(ctx context.Context, r slog.Record) error {
+	msg, truncated := truncateString(r.Message, h.maxMsgLen)
+	truncated = truncated || h.truncated
+
+	nr := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		a, t := h.truncateAttr(a)
+		truncated = truncated || t
+		nr.AddAttrs(a)
+		return true
+	})
+
+	if truncated {
+		nr.AddAttrs(slog.Bool(TruncatedKey, true))
+	}
+
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *TruncateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	truncated := h.truncated
+	nattrs := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		a, t := h.truncateAttr(a)
+		truncated = truncated || t
+		nattrs = append(nattrs, a)
+	}
+
+	return &TruncateHandler{
+		handler:     h.handler.WithAttrs(nattrs),
+		maxMsgLen:   h.maxMsgLen,
+		maxValueLen: h.maxValueLen,
+		truncated:   truncated,
+	}
+}
+
+func (h *TruncateHandler) WithGroup(name string) slog.Handler {
+	return &TruncateHandler{
+		handler:     h.handler.WithGroup(name),
+		maxMsgLen:   h.maxMsgLen,
+		maxValueLen: h.maxValueLen,
+		truncated:   h.truncated,
+	}
+}
+
+func (h *TruncateHandler) truncateAttr(a slog.Attr) (slog.Attr, bool) {
+	if h.maxValueLen <= 0 {
+		return a, false
+	}
+
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString:
+		if s, ok := truncateString(v.String(), h.maxValueLen); ok {
+			return slog.String(a.Key, s), true
+		}
+
+	case slog.KindGroup:
+		truncated := false
+		group := v.Group()
+		nattrs := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			ga, t := h.truncateAttr(ga)
+			truncated = truncated || t
+			nattrs = append(nattrs, ga)
+		}
+		if truncated {
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(nattrs...)}, true
+		}
+
+	case slog.KindAny:
+		var str string
+		switch x := v.Any().(type) {
+		case []byte:
+			str = string(x)
+		case error:
+			// errors are left intact so Error can still unwrap them
+			return a, false
+		case fmt.Stringer:
+			str = x.String()
+		default:
+			// maps, slices and structs are bounded by their encoding,
+			// values within the limit keep their structure
+			data, err := json.Marshal(x)
+			if err != nil {
+				str = fmt.Sprint(x)
+			} else {
+				str = string(data)
+			}
+		}
+		if s, ok := truncateString(str, h.maxValueLen); ok {
+			return slog.String(a.Key, s), true
+		}
+	}
+
+	return a, false
+}
+
+// truncateString cuts s to at most max bytes on a rune boundary
+// and appends an ellipsis, it reports whether s was shortened
+func truncateString(s string, max int) (string, bool) {
+	if max <= 0 || len(s) <= max {
+		return s, false
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + truncateMark, true
+}
@@ -0,0 +1,50 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTruncateHandlerAnyValues(t *testing.T) {
+	large := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		large[fmt.Sprint("key", i)] = i
+	}
+
+	tests := []struct {
+		name      string
+		value     any
+		truncated bool
+	}{
+		{"large map", large, true},
+		{"large slice", make([]int, 1000), true},
+		{"small map", map[string]int{"a": 1}, false},
+		{"struct", struct{ Name string }{strings.Repeat("x", 100)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := NewTruncateHandler(slog.NewJSONHandler(&buf, nil), 0, 32)
+			slog.New(h).Info("x", "value", tt.value)
+
+			var rec map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+				t.Fatal(err)
+			}
+			if got := rec[TruncatedKey] == true; got != tt.truncated {
+				t.Fatalf("truncated = %v, want %v: %s", got, tt.truncated, buf.String())
+			}
+			if s, ok := rec["value"].(string); tt.truncated && (!ok || len(s) > 32+len(truncateMark)) {
+				t.Fatalf("value not bounded: %s", buf.String())
+			}
+			if _, ok := rec["value"].(map[string]any); !tt.truncated && !ok {
+				t.Fatalf("value within the limit lost its structure: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package glog
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// HeartbeatMessage is the message of heartbeat records
+const HeartbeatMessage = "heartbeat"
+
+// Heartbeat logs a liveness record at Info every interval with the
+// uptime of the logger tree, the RuntimeStats group and args, until
+// stop is called. Use it in long running workers
+// so silence in the logs is not ambiguous.
+//
+//	stop := logger.Heartbeat(time.Minute, "worker", "mailer")
+//	defer stop()
+func (c *BaseLogger) Heartbeat(interval time.Duration, args ...any) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				// heartbeats have no meaningful call site
+				r := slog.NewRecord(now, slog.LevelInfo, HeartbeatMessage, 0)
+				r.AddAttrs(argsToAttrs(args)...)
+				r.AddAttrs(slog.Duration("uptime", now.Sub(c.getRoot().startTime)))
+				// unless the enricher adds it already
+				if !c.runtimeStats || c.runtimeStatsLevel > slog.LevelInfo {
+					r.AddAttrs(slog.Any(RuntimeKey, slog.GroupValue(RuntimeStats()...)))
+				}
+				c.LogRecord(c.ctx, r)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,179 @@
+package httplog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// Body attribute keys, a "_truncated" suffixed flag is added when the
+// body was longer than the limit
+const (
+	RequestBodyKey  = "request_body"
+	ResponseBodyKey = "response_body"
+)
+
+// DefaultBodyTypes are the content types captured when WithBodies is
+// given none
+var DefaultBodyTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"text/*",
+}
+
+// BodyRedactor rewrites a captured body before it is logged. body may
+// be cut at the capture limit.
+type BodyRedactor func(contentType string, body []byte) []byte
+
+// WithBodies logs the first limit bytes of request and response bodies
+// whose content type matches types, "text/*" matches any text type.
+// Bodies are passed through the WithBodyRedactor hooks first.
+func WithBodies(limit int, types ...string) Option {
+	return func(c *config) {
+		c.bodyLimit = limit
+		c.bodyTypes = types
+		if len(types) == 0 {
+			c.bodyTypes = DefaultBodyTypes
+		}
+	}
+}
+
+// WithBodyRedactor adds a hook applied to captured bodies, hooks run in
+// the order they were added
+func WithBodyRedactor(fn BodyRedactor) Option {
+	return func(c *config) {
+		c.bodyRedactors = append(c.bodyRedactors, fn)
+	}
+}
+
+// RedactJSONKeys returns a BodyRedactor that replaces the values of
+// keys in JSON bodies with glog.RedactedValue, at any depth and
+// matched case insensitively. JSON bodies that do not parse, e.g.
+// because they were cut at the limit, are withheld entirely.
+func RedactJSONKeys(keys ...string) BodyRedactor {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = true
+	}
+
+	return func(contentType string, body []byte) []byte {
+		if !isJSON(contentType) {
+			return body
+		}
+
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			return []byte(glog.RedactedValue)
+		}
+		out, err := json.Marshal(redactJSON(v, set))
+		if err != nil {
+			return []byte(glog.RedactedValue)
+		}
+		return out
+	}
+}
+
+func redactJSON(v any, keys map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if keys[strings.ToLower(k)] {
+				v[k] = glog.RedactedValue
+				continue
+			}
+			v[k] = redactJSON(val, keys)
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = redactJSON(val, keys)
+		}
+	}
+	return v
+}
+
+func isJSON(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// bodyCapture keeps the first limit bytes written to it. It is safe
+// for concurrent use, as outbound request bodies are written by the
+// transport while the response is read.
+type bodyCapture struct {
+	limit int
+
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+}
+
+func (b *bodyCapture) write(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	room := b.limit - len(b.buf)
+	if len(p) > room {
+		b.truncated = true
+		p = p[:max(room, 0)]
+	}
+	b.buf = append(b.buf, p...)
+}
+
+// snapshot returns a copy of the bytes captured so far
+func (b *bodyCapture) snapshot() ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.buf), b.truncated
+}
+
+// newCapture returns a capture when bodies are logged
+func (c *config) newCapture() *bodyCapture {
+	if c.bodyLimit <= 0 {
+		return nil
+	}
+	return &bodyCapture{limit: c.bodyLimit}
+}
+
+// bodyAttrs returns the attributes for a captured body of contentType,
+// nothing when the type is not allowed
+func (c *config) bodyAttrs(key, contentType string, b *bodyCapture) []slog.Attr {
+	if b == nil || !c.allowBody(contentType) {
+		return nil
+	}
+
+	body, truncated := b.snapshot()
+	if len(body) == 0 {
+		return nil
+	}
+	for _, fn := range c.bodyRedactors {
+		body = fn(contentType, body)
+	}
+
+	attrs := []slog.Attr{slog.String(key, string(body))}
+	if truncated {
+		attrs = append(attrs, slog.Bool(key+"_truncated", true))
+	}
+	return attrs
+}
+
+func (c *config) allowBody(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.bodyTypes {
+		if t == mt {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(mt, prefix) {
+			return true
+		}
+	}
+	return false
+}
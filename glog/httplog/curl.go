@@ -0,0 +1,157 @@
+package httplog
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// CurlKey holds the curl command of an outbound request
+const CurlKey = "curl"
+
+// maxCurlBody is the largest request body rendered in curl commands
+const maxCurlBody = 64 << 10
+
+// DefaultSecretHeaders are masked in curl commands
+var DefaultSecretHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// DefaultSecretParams are query parameters masked in curl commands,
+// matched case insensitively
+var DefaultSecretParams = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"password",
+	"secret",
+	"token",
+}
+
+// WithCurl makes Transport log every outbound request as a curl command
+// at Trace, before it is sent. Values of the DefaultSecretHeaders
+// headers, the DefaultSecretParams query parameters and the headers
+// and parameters named by secrets are masked, the URL password is
+// removed and the body goes through the WithBodyRedactor hooks. Bodies
+// are only rendered when the request can replay them, see
+// http.Request.GetBody.
+func WithCurl(secrets ...string) Option {
+	return func(c *config) {
+		c.curl = true
+		c.curlSecrets = map[string]bool{}
+		for _, h := range append(slices.Clone(DefaultSecretHeaders), secrets...) {
+			c.curlSecrets[http.CanonicalHeaderKey(h)] = true
+		}
+		c.curlParams = map[string]bool{}
+		for _, p := range append(slices.Clone(DefaultSecretParams), secrets...) {
+			c.curlParams[strings.ToLower(p)] = true
+		}
+	}
+}
+
+func (t *Transport) logCurl(req *http.Request) {
+	if !t.logger.Enabled(glog.LevelTrace) {
+		return
+	}
+	rec := slog.NewRecord(time.Now(), glog.LevelTrace, "curl", 0)
+	rec.AddAttrs(slog.String(CurlKey, t.cfg.curlCommand(req)))
+	t.logger.LogRecord(req.Context(), rec)
+}
+
+// curlCommand renders req as a shell command line
+func (c *config) curlCommand(req *http.Request) string {
+	var b strings.Builder
+	b.WriteString("curl")
+
+	body := c.curlBody(req)
+	if req.Method != http.MethodGet || body != "" {
+		b.WriteString(" -X ")
+		b.WriteString(req.Method)
+	}
+	b.WriteString(" ")
+	b.WriteString(shellQuote(c.curlURL(req.URL)))
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			if c.curlSecrets[http.CanonicalHeaderKey(k)] {
+				v = glog.RedactedValue
+			}
+			b.WriteString(" -H ")
+			b.WriteString(shellQuote(k + ": " + v))
+		}
+	}
+
+	if body != "" {
+		b.WriteString(" --data-binary ")
+		b.WriteString(shellQuote(body))
+	}
+	return b.String()
+}
+
+// curlURL renders u without its password and with the values of the
+// secret query parameters masked, keeping the order of the others
+func (c *config) curlURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key, _, ok := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if ok && err == nil && c.curlParams[strings.ToLower(name)] {
+			params[i] = key + "=" + url.QueryEscape(glog.RedactedValue)
+		}
+	}
+
+	masked := *u
+	masked.RawQuery = strings.Join(params, "&")
+	return masked.Redacted()
+}
+
+func (c *config) curlBody(req *http.Request) string {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if req.ContentLength < 0 || req.ContentLength > maxCurlBody {
+		return ""
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(io.LimitReader(rc, maxCurlBody))
+	if err != nil {
+		return ""
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	for _, fn := range c.bodyRedactors {
+		body = fn(contentType, body)
+	}
+	return string(body)
+}
+
+// shellQuote single quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,378 @@
+// Package httplog provides net/http middleware that logs requests
+// through glog. Request scoped attributes are stored in the request
+// context, so records logged with r.Context() inside handlers carry
+// them too, and handlers add attributes to the request record with
+// glog.EventFromContext(r.Context()).
+//
+//	mux := http.NewServeMux()
+//	srv := httplog.Middleware(logger, httplog.WithRecovery(false))(mux)
+package httplog
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// RequestIDHeader is read to tag records with the request ID
+const RequestIDHeader = "X-Request-Id"
+
+// DebugTokenHeader carries the secret that elevates a request to
+// Trace, see WithDebugTokens
+const DebugTokenHeader = "X-Debug-Token"
+
+// Request scoped attribute keys
+const (
+	MethodKey    = "method"
+	PathKey      = "path"
+	RequestIDKey = "request_id"
+	PanicKey     = "panic"
+	DebugKey     = "debug"
+)
+
+// Access record attribute keys
+const (
+	StatusKey       = "status"
+	BytesKey        = "bytes"
+	RequestBytesKey = "request_bytes"
+	DurationKey     = "duration"
+	TTFBKey         = "ttfb"
+	UserAgentKey    = "user_agent"
+	RefererKey      = "referer"
+	TLSKey          = "tls"
+	RouteKey        = "route"
+	RemoteAddrKey   = "remote_addr"
+	ProtoKey        = "proto"
+	QueryKey        = "query"
+)
+
+// Field selects optional attributes of the access record, status and
+// duration are always included
+type Field uint
+
+const (
+	// FieldLatency adds the time to the first response byte
+	FieldLatency Field = 1 << iota
+	// FieldRequestSize adds the bytes read from the request body
+	FieldRequestSize
+	// FieldResponseSize adds the bytes written to the response body
+	FieldResponseSize
+	FieldUserAgent
+	FieldReferer
+	// FieldTLS adds the TLS version of the connection, if any
+	FieldTLS
+	// FieldRoute adds the matched route pattern, see WithRouteFunc
+	FieldRoute
+	FieldRemoteAddr
+	FieldProto
+	FieldQuery
+
+	// DefaultFields are logged unless changed with WithFields and
+	// WithoutFields
+	DefaultFields = FieldLatency | FieldRequestSize | FieldResponseSize |
+		FieldUserAgent | FieldReferer | FieldTLS | FieldRoute | FieldRemoteAddr
+	// AllFields enables every optional field
+	AllFields = DefaultFields | FieldProto | FieldQuery
+)
+
+// Option configures the middleware
+type Option func(*config)
+
+type config struct {
+	recover bool
+	repanic bool
+	fields  Field
+	route   func(*http.Request) string
+
+	bodyLimit     int
+	bodyTypes     []string
+	bodyRedactors []BodyRedactor
+
+	curl        bool
+	curlSecrets map[string]bool
+	curlParams  map[string]bool
+
+	rollout     float64
+	debugTokens [][]byte
+
+	deferred     bool
+	deferredSlow time.Duration
+}
+
+// WithFields enables fields in the access record
+func WithFields(fields Field) Option {
+	return func(c *config) {
+		c.fields |= fields
+	}
+}
+
+// WithoutFields disables fields in the access record
+//
+//	httplog.WithoutFields(httplog.FieldUserAgent | httplog.FieldReferer)
+func WithoutFields(fields Field) Option {
+	return func(c *config) {
+		c.fields &^= fields
+	}
+}
+
+// WithRouteFunc sets how the matched route is read, for routers other
+// than http.ServeMux. It is called after the handler returns. By
+// default the pattern of the request is used.
+func WithRouteFunc(fn func(*http.Request) string) Option {
+	return func(c *config) {
+		c.route = fn
+	}
+}
+
+// WithDebugRollout logs the given fraction of requests at Debug,
+// chosen by a hash of their request ID, see glog.ContextWithRollout.
+// Requests without an ID are never elevated.
+func WithDebugRollout(fraction float64) Option {
+	return func(c *config) {
+		c.rollout = fraction
+	}
+}
+
+// WithDebugTokens logs requests at Trace when their DebugTokenHeader
+// matches one of tokens, so support engineers can debug a single
+// request in production. Their records are tagged with debug=true.
+func WithDebugTokens(tokens ...string) Option {
+	return func(c *config) {
+		for _, token := range tokens {
+			if token != "" {
+				c.debugTokens = append(c.debugTokens, []byte(token))
+			}
+		}
+	}
+}
+
+// WithDeferredDebug holds the Debug and Trace records of each request
+// and writes them only when the request fails, an Error record is
+// logged or it takes longer than slow, otherwise they are discarded.
+// A zero slow keeps them for failed requests only. Requests with a
+// debug token are not deferred. See glog.ContextWithDeferred.
+func WithDeferredDebug(slow time.Duration) Option {
+	return func(c *config) {
+		c.deferred = true
+		c.deferredSlow = slow
+	}
+}
+
+// WithRecovery recovers panics in handlers, logs them at Error with
+// the stack of the panicking goroutine and responds 500. With repanic
+// the panic is raised again after that, so development servers still
+// crash loudly.
+func WithRecovery(repanic bool) Option {
+	return func(c *config) {
+		c.recover = true
+		c.repanic = repanic
+	}
+}
+
+// Middleware logs a record for every request through logger, at Error
+// for 5xx responses, Warn for 4xx and Info otherwise. The record is the
+// canonical event of the request, handlers add to it with
+// glog.EventFromContext(r.Context()) and it is emitted once the
+// response is written.
+func Middleware(logger *glog.BaseLogger, opts ...Option) func(http.Handler) http.Handler {
+	cfg := &config{
+		fields: DefaultFields,
+		route:  func(r *http.Request) string { return r.Pattern },
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx := glog.TraceContextFromHeader(r.Context(), r.Header)
+			ctx = glog.AppendCtx(ctx, requestAttrs(r)...)
+			if cfg.rollout > 0 {
+				ctx = glog.ContextWithRollout(ctx, r.Header.Get(RequestIDHeader), cfg.rollout)
+			}
+			var deferred *glog.DeferredBuffer
+			if cfg.debugToken(r) {
+				ctx = glog.ContextWithLevel(ctx, glog.LevelTrace)
+				ctx = glog.AppendCtx(ctx, slog.Bool(DebugKey, true))
+			} else if cfg.deferred {
+				ctx, deferred = glog.ContextWithDeferred(ctx, 0)
+			}
+			ctx, ev := logger.NewEvent(ctx, "http request")
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w, start: start, capture: cfg.newCapture()}
+			var body *countingReader
+			if r.Body != nil && r.Body != http.NoBody && (cfg.fields&FieldRequestSize != 0 || cfg.bodyLimit > 0) {
+				body = &countingReader{ReadCloser: r.Body, capture: cfg.newCapture()}
+				r.Body = body
+			}
+			access := func() {
+				// error records flush the deferred records themselves
+				if deferred != nil && cfg.deferredSlow > 0 && time.Since(start) > cfg.deferredSlow {
+					deferred.Flush()
+				}
+				ev.SetLevel(statusLevel(rw.statusCode()))
+				ev.AddAttrs(cfg.accessAttrs(r, rw, body)...)
+				ev.Emit()
+				if deferred != nil {
+					deferred.Discard()
+				}
+			}
+
+			panicked := true
+			defer func() {
+				if !panicked || !cfg.recover {
+					access()
+					return
+				}
+
+				v := recover()
+				if v == http.ErrAbortHandler {
+					// net/http aborts the response silently
+					panic(v)
+				}
+
+				logPanic(ctx, logger, v, glog.PanicPC(), glog.PanicStack())
+				if !rw.wroteHeader {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				access()
+
+				if cfg.repanic {
+					panic(v)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+			panicked = false
+		})
+	}
+}
+
+// debugToken reports whether r carries one of the debug tokens
+func (c *config) debugToken(r *http.Request) bool {
+	if len(c.debugTokens) == 0 {
+		return false
+	}
+	got := []byte(r.Header.Get(DebugTokenHeader))
+	if len(got) == 0 {
+		return false
+	}
+	for _, token := range c.debugTokens {
+		if subtle.ConstantTimeCompare(got, token) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func requestAttrs(r *http.Request) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(MethodKey, r.Method),
+		slog.String(PathKey, r.URL.Path),
+	}
+	if id := r.Header.Get(RequestIDHeader); id != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, id))
+	}
+	return attrs
+}
+
+func logPanic(ctx context.Context, logger *glog.BaseLogger, v any, pc uintptr, stack []glog.CrashFrame) {
+	attr := slog.Any(PanicKey, fmt.Sprint(v))
+	if err, ok := v.(error); ok {
+		attr = glog.Err(err)
+	}
+
+	rec := slog.NewRecord(time.Now(), slog.LevelError, "panic recovered", pc)
+	rec.AddAttrs(attr, slog.Any(glog.StackKey, stack))
+	logger.LogRecord(ctx, rec)
+}
+
+// accessAttrs returns the access record attributes of the finished
+// request, the event adds the duration
+func (c *config) accessAttrs(r *http.Request, rw *responseWriter, body *countingReader) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 12)
+	attrs = append(attrs, slog.Int(StatusKey, rw.statusCode()))
+
+	if c.fields&FieldLatency != 0 && rw.wroteHeader {
+		attrs = append(attrs, slog.Duration(TTFBKey, rw.ttfb))
+	}
+	if c.fields&FieldRequestSize != 0 {
+		var n int64
+		if body != nil {
+			n = body.n
+		}
+		attrs = append(attrs, slog.Int64(RequestBytesKey, n))
+	}
+	if c.fields&FieldResponseSize != 0 {
+		attrs = append(attrs, slog.Int64(BytesKey, rw.bytes))
+	}
+	if c.fields&FieldUserAgent != 0 {
+		if ua := r.UserAgent(); ua != "" {
+			attrs = append(attrs, slog.String(UserAgentKey, ua))
+		}
+	}
+	if c.fields&FieldReferer != 0 {
+		if ref := r.Referer(); ref != "" {
+			attrs = append(attrs, slog.String(RefererKey, ref))
+		}
+	}
+	if c.fields&FieldTLS != 0 && r.TLS != nil {
+		attrs = append(attrs, slog.String(TLSKey, tls.VersionName(r.TLS.Version)))
+	}
+	if c.fields&FieldRoute != 0 && c.route != nil {
+		if route := c.route(r); route != "" {
+			attrs = append(attrs, slog.String(RouteKey, route))
+		}
+	}
+	if c.fields&FieldRemoteAddr != 0 && r.RemoteAddr != "" {
+		attrs = append(attrs, slog.String(RemoteAddrKey, r.RemoteAddr))
+	}
+	if c.fields&FieldProto != 0 {
+		attrs = append(attrs, slog.String(ProtoKey, r.Proto))
+	}
+	if c.fields&FieldQuery != 0 && r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String(QueryKey, r.URL.RawQuery))
+	}
+	if body != nil {
+		attrs = append(attrs, c.bodyAttrs(RequestBodyKey, r.Header.Get("Content-Type"), body.capture)...)
+	}
+	attrs = append(attrs, c.bodyAttrs(ResponseBodyKey, rw.Header().Get("Content-Type"), rw.capture)...)
+	return attrs
+}
+
+func statusLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+// countingReader counts the bytes read from a body and captures them
+// when capture is set
+type countingReader struct {
+	io.ReadCloser
+	n       int64
+	capture *bodyCapture
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	if r.capture != nil {
+		r.capture.write(p[:n])
+	}
+	return n, err
+}
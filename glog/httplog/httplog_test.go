@@ -0,0 +1,83 @@
+package httplog
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+func TestMiddlewareEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantLevel string
+		wantAttrs map[string]any
+	}{
+		{
+			name: "handler attributes",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				ev := glog.EventFromContext(r.Context())
+				ev.Add("user", "alice")
+				ev.Inc("db_calls", 1)
+				ev.Inc("db_calls", 1)
+			},
+			wantLevel: "info",
+			wantAttrs: map[string]any{"user": "alice", "db_calls": float64(2), StatusKey: float64(200)},
+		},
+		{
+			name: "client error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantLevel: "warn",
+			wantAttrs: map[string]any{StatusKey: float64(404)},
+		},
+		{
+			name: "handler raises the level",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				glog.EventFromContext(r.Context()).SetLevel(slog.LevelWarn)
+			},
+			wantLevel: "warn",
+			wantAttrs: map[string]any{StatusKey: float64(200)},
+		},
+		{
+			name: "handler error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				glog.EventFromContext(r.Context()).SetError(errors.New("boom"))
+			},
+			wantLevel: "error",
+			wantAttrs: map[string]any{StatusKey: float64(200)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out syncBuffer
+			logger := glog.NewLogger(glog.WithOutput(&out), glog.WithLevel(glog.Debug))
+			srv := Middleware(logger)(tt.handler)
+
+			srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x", nil))
+
+			recs := out.records(t)
+			if len(recs) != 1 {
+				t.Fatalf("got %d records, want 1", len(recs))
+			}
+			rec := recs[0]
+			if rec["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %v", rec["level"], tt.wantLevel)
+			}
+			if _, ok := rec[DurationKey]; !ok {
+				t.Errorf("record has no %s", DurationKey)
+			}
+			for k, want := range tt.wantAttrs {
+				if rec[k] != want {
+					t.Errorf("%s = %v, want %v", k, rec[k], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package httplog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// URLKey holds the URL of outbound requests, without the password
+const URLKey = "url"
+
+// Transport is an http.RoundTripper that logs outbound requests. It
+// accepts the same options as Middleware, those that only apply to
+// served requests are ignored.
+//
+//	client := &http.Client{Transport: httplog.NewTransport(logger, nil)}
+type Transport struct {
+	base   http.RoundTripper
+	logger *glog.BaseLogger
+	cfg    *config
+}
+
+// NewTransport returns a Transport that sends requests with base, or
+// http.DefaultTransport when base is nil
+func NewTransport(logger *glog.BaseLogger, base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &Transport{base: base, logger: logger, cfg: cfg}
+}
+
+// RoundTrip implements http.RoundTripper. Captured response bodies
+// are read as the caller reads them, the record is logged when the
+// body is read to the end or closed.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.curl {
+		t.logCurl(req)
+	}
+
+	start := time.Now()
+	ctx := req.Context()
+
+	reqType := req.Header.Get("Content-Type")
+	var reqBody *bodyCapture
+	if req.Body != nil && req.Body != http.NoBody && t.cfg.bodyLimit > 0 && t.cfg.allowBody(reqType) {
+		reqBody, req = t.captureRequest(req)
+	}
+
+	attrs := []slog.Attr{
+		slog.String(MethodKey, req.Method),
+		slog.String(URLKey, req.URL.Redacted()),
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	attrs = append(attrs, slog.Duration(DurationKey, time.Since(start)))
+	attrs = append(attrs, t.cfg.bodyAttrs(RequestBodyKey, reqType, reqBody)...)
+
+	if err != nil {
+		t.log(ctx, slog.LevelError, append(attrs, glog.Err(err)))
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int(StatusKey, resp.StatusCode))
+	level := statusLevel(resp.StatusCode)
+
+	respType := resp.Header.Get("Content-Type")
+	capture := t.cfg.newCapture()
+	if capture == nil || resp.Body == nil || resp.Body == http.NoBody || !t.cfg.allowBody(respType) {
+		t.log(ctx, level, attrs)
+		return resp, nil
+	}
+
+	resp.Body = &teeBody{
+		ReadCloser: resp.Body,
+		capture:    capture,
+		done: func() {
+			t.log(ctx, level, append(attrs, t.cfg.bodyAttrs(ResponseBodyKey, respType, capture)...))
+		},
+	}
+	return resp, nil
+}
+
+// captureRequest returns the capture of the request body and the
+// request to send. Bodies that can be replayed are read from a copy,
+// the others are captured through a tee as the transport sends them.
+func (t *Transport) captureRequest(req *http.Request) (*bodyCapture, *http.Request) {
+	capture := t.cfg.newCapture()
+
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			// read one byte more than the limit to tell if it was cut
+			body, _ := io.ReadAll(io.LimitReader(rc, int64(capture.limit)+1))
+			rc.Close()
+			capture.write(body)
+			return capture, req
+		}
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = &countingReader{ReadCloser: req.Body, capture: capture}
+	return capture, req
+}
+
+func (t *Transport) log(ctx context.Context, level slog.Level, attrs []slog.Attr) {
+	rec := slog.NewRecord(time.Now(), level, "http client request", 0)
+	rec.AddAttrs(attrs...)
+	t.logger.LogRecord(ctx, rec)
+}
+
+// teeBody captures a response body as it is read and calls done once,
+// at the end of the body or when it is closed
+type teeBody struct {
+	io.ReadCloser
+	capture *bodyCapture
+	once    sync.Once
+	done    func()
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture.write(p[:n])
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+	return n, err
+}
+
+func (b *teeBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
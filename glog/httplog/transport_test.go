@@ -0,0 +1,167 @@
+package httplog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// syncBuffer collects log output written from several goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records decodes the JSON records written so far
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func newTestTransport(out io.Writer, opts ...Option) *http.Client {
+	logger := glog.NewLogger(glog.WithOutput(out), glog.WithLevel(glog.Debug))
+	return &http.Client{Transport: NewTransport(logger, nil, opts...)}
+}
+
+func TestTransportStreamingResponse(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: one\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var out syncBuffer
+	client := newTestTransport(&out, WithBodies(1024))
+
+	done := make(chan *http.Response, 1)
+	go func() {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+
+	var resp *http.Response
+	select {
+	case resp = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RoundTrip blocked on a streaming response")
+	}
+	if resp == nil {
+		return
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "data: one\n" {
+		t.Fatalf("read %q, %v", line, err)
+	}
+	resp.Body.Close()
+
+	recs := out.records(t)
+	if len(recs) != 1 {
+		t.Fatalf("got %d records, want 1 after Close", len(recs))
+	}
+	if body, _ := recs[0][ResponseBodyKey].(string); !strings.HasPrefix(body, "data: one") {
+		t.Fatalf("response body = %q", body)
+	}
+}
+
+func TestTransportBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		respType string
+		body     func() io.Reader
+		wantReq  string
+		wantResp string
+	}{
+		{
+			name:     "replayable request body",
+			respType: "application/json",
+			body:     func() io.Reader { return strings.NewReader(`{"a":1}`) },
+			wantReq:  `{"a":1}`,
+			wantResp: `{"ok":true}`,
+		},
+		{
+			name:     "streamed request body",
+			respType: "application/json",
+			// a reader without a known type has no GetBody
+			body:     func() io.Reader { return io.MultiReader(strings.NewReader(`{"a":2}`)) },
+			wantReq:  `{"a":2}`,
+			wantResp: `{"ok":true}`,
+		},
+		{
+			name:     "response type not allowed",
+			respType: "image/png",
+			body:     func() io.Reader { return strings.NewReader(`{"a":3}`) },
+			wantReq:  `{"a":3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out syncBuffer
+			client := newTestTransport(&out, WithBodies(1024))
+
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+"?type="+tt.respType, tt.body())
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			recs := out.records(t)
+			if len(recs) != 1 {
+				t.Fatalf("got %d records, want 1", len(recs))
+			}
+			if got, _ := recs[0][RequestBodyKey].(string); got != tt.wantReq {
+				t.Errorf("request body = %q, want %q", got, tt.wantReq)
+			}
+			if got, _ := recs[0][ResponseBodyKey].(string); got != tt.wantResp {
+				t.Errorf("response body = %q, want %q", got, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// responseWriter records the status, size and time to first byte of
+// the response, and its body when capture is set
+type responseWriter struct {
+	http.ResponseWriter
+	start       time.Time
+	ttfb        time.Duration
+	status      int
+	bytes       int64
+	wroteHeader bool
+	capture     *bodyCapture
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.ttfb = time.Since(w.start)
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	if w.capture != nil {
+		w.capture.write(b[:n])
+	}
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection to the handler, e.g. for websockets.
+// The response is logged as switching protocols unless a status was
+// written.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.ttfb = time.Since(w.start)
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// ReadFrom keeps the sendfile path of the underlying writer, e.g. for
+// http.ServeContent, unless the body is captured
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if !ok || w.capture != nil {
+		return io.Copy(writerOnly{w}, r)
+	}
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := rf.ReadFrom(r)
+	w.bytes += n
+	return n, err
+}
+
+// writerOnly hides ReadFrom so io.Copy does not call it again
+type writerOnly struct {
+	io.Writer
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
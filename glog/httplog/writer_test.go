@@ -0,0 +1,86 @@
+package httplog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-logger/glog"
+)
+
+// readFromRecorder records whether ReadFrom was used
+type readFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (r *readFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFrom = true
+	return io.Copy(r.ResponseRecorder, src)
+}
+
+func TestResponseWriterReadFrom(t *testing.T) {
+	tests := []struct {
+		name         string
+		capture      *bodyCapture
+		wantReadFrom bool
+	}{
+		{"passthrough", nil, true},
+		{"captured", &bodyCapture{limit: 64}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &readFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+			w := &responseWriter{ResponseWriter: rec, capture: tt.capture}
+
+			n, err := w.ReadFrom(strings.NewReader("hello"))
+			if err != nil || n != 5 {
+				t.Fatalf("ReadFrom() = %d, %v", n, err)
+			}
+			if rec.readFrom != tt.wantReadFrom {
+				t.Errorf("underlying ReadFrom used = %v, want %v", rec.readFrom, tt.wantReadFrom)
+			}
+			if w.bytes != 5 || w.statusCode() != http.StatusOK || rec.Body.String() != "hello" {
+				t.Errorf("bytes = %d, status = %d, body = %q", w.bytes, w.statusCode(), rec.Body.String())
+			}
+			if tt.capture != nil && string(tt.capture.buf) != "hello" {
+				t.Errorf("captured %q", tt.capture.buf)
+			}
+		})
+	}
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	var out syncBuffer
+	logger := glog.NewLogger(glog.WithOutput(&out))
+
+	srv := httptest.NewServer(Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: close\r\n\r\n")
+		rw.Flush()
+	})))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	srv.Close()
+	recs := out.records(t)
+	if len(recs) != 1 || recs[0][StatusKey] != float64(http.StatusSwitchingProtocols) {
+		t.Fatalf("records = %v", recs)
+	}
+}
@@ -0,0 +1,49 @@
+package glog
+
+import "log/slog"
+
+// Default keys for the standard fields
+const (
+	TimeKey    = "ts"
+	LevelKey   = slog.LevelKey
+	MessageKey = slog.MessageKey
+	LoggerKey  = "logger"
+	ErrorKey   = "error"
+	StackKey   = "stack"
+)
+
+// fieldKeys holds the output names of the standard fields
+type fieldKeys struct {
+	time    string
+	level   string
+	message string
+	logger  string
+	error   string
+	stack   string
+}
+
+func defaultFieldKeys() fieldKeys {
+	return fieldKeys{
+		time:    TimeKey,
+		level:   LevelKey,
+		message: MessageKey,
+		logger:  LoggerKey,
+		error:   ErrorKey,
+		stack:   StackKey,
+	}
+}
+
+// rename maps a top level attribute key to its configured name
+func (k fieldKeys) rename(key string) string {
+	switch key {
+	case MessageKey:
+		return k.message
+	case LoggerKey:
+		return k.logger
+	case ErrorKey:
+		return k.error
+	case StackKey:
+		return k.stack
+	}
+	return key
+}
@@ -6,30 +6,160 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"os"
+	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var DefaultLogLevel = Info
 
-// BaseLogger implements both Logger and LoggerProvider interfaces
+// BaseLogger implements both Logger and LoggerProvider interfaces.
+//
+// A BaseLogger is safe for concurrent use. Logging methods never take
+// locks, configuration changes (WithLevel, WithLoggerType, Focus) and
+// GetLogger may run while other goroutines log, and records see either
+// the old or the new configuration. With and WithContext return copies
+// and never modify the receiver.
 type BaseLogger struct {
-	mu       sync.RWMutex
-	logger   *slog.Logger
-	root     *BaseLogger
-	loggers  map[string]*BaseLogger
-	opts     *slog.HandlerOptions
-	ctx      context.Context
-	focused  bool
-	focusMap map[string]bool
-	stdout   io.Writer
-
-	level      string
-	addSource  bool
-	loggerType string
-	name       string
+	mu     sync.RWMutex
+	logger atomic.Pointer[slog.Logger]
+	// sink writes to base, skipping the filters of the chain
+	sink    atomic.Pointer[slog.Logger]
+	root    *BaseLogger
+	loggers map[string]*BaseLogger
+	opts    *slog.HandlerOptions
+	ctx     context.Context
+	focus   atomic.Pointer[focusSet]
+	stdout  io.Writer
+	// stderr receives records at Warn and above when set
+	stderr io.Writer
+
+	// base is the output part of the handler chain, children share
+	// it with their parent until their output configuration changes
+	base     slog.Handler
+	levelVar *slog.LevelVar
+	discard  bool
+
+	level       string
+	addSource   bool
+	loggerType  string
+	name        string
+	consoleOpts []ColorConsoleOption
+	maxMsgLen   int
+	maxValueLen int
+	maxAttrs    int
+	maxSize     int
+	flatten     bool
+	expand      bool
+
+	sourceFormat     SourceFormat
+	sourceTrimPrefix string
+	sourceFunc       bool
+
+	callerSkip int
+	badKeys    BadKeyMode
+	dedup      bool
+	dedupMode  DedupMode
+
+	stackTrace bool
+	stackLevel slog.Level
+	stackOff   bool
+	stackDepth int
+	stackSkip  int
+
+	timeFormat   string
+	timeLocation *time.Location
+
+	keys fieldKeys
+
+	replaceAttrs []func(groups []string, a slog.Attr) slog.Attr
+	defaultAttrs []slog.Attr
+	enrichers    []Enricher
+	// withArgs are the arguments of With, kept to rebuild copies
+	withArgs []any
+
+	requiredAttrs      []string
+	requiredAttrsLevel slog.Level
+	requiredAttrsMode  RequiredAttrsMode
+
+	sequence bool
+	seq      *atomic.Uint64
+
+	uptime    bool
+	startTime time.Time
+
+	runtimeStats      bool
+	runtimeStatsLevel slog.Level
+
+	hmacKey []byte
+
+	spanExtractor SpanExtractor
+	// sampledLevel applies to records whose context has a sampled span
+	sampledLevel   slog.Level
+	sampledLevelOn bool
+
+	stream *LogStream
+	subs   *subscriptions
+	drops  *dropStats
+	// metricSubs observe records before sampling and the breaker drop
+	// them, see WithMetrics
+	metricSubs *subscriptions
+
+	recentSize int
+	recent     *recentBuffer
+
+	crashDir string
+
+	sampleRates    map[slog.Level]float64
+	sampleInterval time.Duration
+	sampling       *sampleState
+
+	breakerThreshold int
+	breakerWindow    time.Duration
+	breakerCooldown  time.Duration
+	breaker          *breakerState
+
+	asyncSize int
+	asyncOpts []AsyncOption
+	async     *AsyncHandler
+
+	// closers are resources owned by the root logger
+	closers []io.Closer
+
+	flushTimeout time.Duration
+
+	// controlPath is the control socket served once configured
+	controlPath string
+
+	// development makes DPanic panic
+	development bool
+
+	// exitCodes maps error codes to the exit codes of Fatal
+	exitCodes   map[int]int
+	exitDefault int
+
+	// overriding is set while GetLogger applies options to a child
+	overriding bool
+
+	// errs are problems found while applying options, NewLoggerE
+	// reports them and NewLogger ignores them
+	errs []error
+
+	tenants       *tenantConfig
+	tenantLoggers map[string]*BaseLogger
+
+	auditOut         io.Writer
+	auditChain       bool
+	auditChainKey    []byte
+	auditAnchorEvery int
+	auditOnce        sync.Once
+	audit            *auditPipeline
 }
 
 func Arg(key string, value any) any {
@@ -41,21 +171,64 @@ func Args(args ...any) any {
 }
 
 func NewLogger(options ...Option) *BaseLogger {
+	c := newLogger(options)
+	c.configureLogger()
+	_ = c.start()
+	return c
+}
+
+// NewLoggerE is like NewLogger but returns an error describing invalid
+// levels, formats, writers or output files instead of falling back to
+// the defaults
+func NewLoggerE(options ...Option) (*BaseLogger, error) {
+	c := newLogger(options)
+	if err := c.validate(); err != nil {
+		for _, closer := range c.closers {
+			closer.Close()
+		}
+		return nil, err
+	}
+	c.configureLogger()
+	if err := c.start(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// start runs the services set by options, once the logger is
+// configured so they never see a partially built logger
+func (c *BaseLogger) start() error {
+	if c.controlPath == "" {
+		return nil
+	}
+	s, err := c.ServeControl(c.controlPath)
+	if err != nil {
+		return err
+	}
+	c.closers = append(c.closers, s)
+	return nil
+}
+
+// newLogger returns an unconfigured root logger with options applied
+func newLogger(options []Option) *BaseLogger {
 	c := &BaseLogger{
-		ctx:       context.Background(),
-		level:     DefaultLogLevel,
-		addSource: true,
-		loggers:   map[string]*BaseLogger{},
-		focusMap:  map[string]bool{},
-		stdout:    os.Stdout,
+		ctx:        context.Background(),
+		level:      DefaultLogLevel,
+		addSource:  true,
+		loggers:    map[string]*BaseLogger{},
+		stdout:     os.Stdout,
+		keys:       defaultFieldKeys(),
+		startTime:  time.Now(),
+		subs:       newSubscriptions(),
+		metricSubs: newSubscriptions(),
+		drops:      newDropStats(),
 	}
 
 	for _, option := range options {
 		option(c)
 	}
 
-	c.configureLogger()
-
 	// TODO: refactor rename root to parent
 	// TODO: refactor root should have not parent
 	if c.root == nil {
@@ -65,32 +238,174 @@ func NewLogger(options ...Option) *BaseLogger {
 	return c
 }
 
-// WithLevel sets the log level and returns the logger
+// validate checks the options applied to a new logger
+func (c *BaseLogger) validate() error {
+	errs := c.errs
+
+	if _, err := ParseLevel(c.level); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch c.loggerType {
+	case "", LoggerTypeJSON, LoggerTypeConsole, LoggerTypePretty:
+	default:
+		errs = append(errs, fmt.Errorf("glog: unknown logger type %q", c.loggerType))
+	}
+
+	if c.stdout == nil {
+		errs = append(errs, errors.New("glog: output writer is nil"))
+	}
+
+	for level, rate := range c.sampleRates {
+		if rate < 0 || rate > 1 {
+			errs = append(errs, fmt.Errorf("glog: sampling rate for %s must be between 0 and 1, got %v", level, rate))
+		}
+	}
+
+	if c.asyncSize < 0 {
+		errs = append(errs, fmt.Errorf("glog: async queue size must not be negative, got %d", c.asyncSize))
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithLevel sets the log level and returns the logger. Copies made
+// with With, WithContext and WithCallerSkip share the level with the
+// logger they were made from, so setting it on any of them changes it
+// for all. Children from GetLogger have their own level. Use
+// ContextWithLevel to change the level of a single request.
 func (c *BaseLogger) WithLevel(level string) *BaseLogger {
-	c.level = level
-	c.configureLogger()
+	c.levelVar.Set(getLevel(level))
 	return c
 }
 
-// WithLevel sets the log level and returns the logger
+// Level returns the current level of the logger
+func (c *BaseLogger) Level() slog.Level {
+	return c.levelVar.Level()
+}
+
+// WithContext returns a copy of the logger bound to ctx, it shares
+// the level of c, see WithLevel
 func (c *BaseLogger) WithContext(ctx context.Context) Logger {
-	newLogger := &BaseLogger{
-		logger:     c.logger,
-		root:       c.root,
-		loggers:    c.loggers,
-		opts:       c.opts,
-		ctx:        ctx,
-		name:       c.name,
-		focusMap:   c.focusMap,
-		level:      c.level,
-		addSource:  c.addSource,
-		loggerType: c.loggerType,
-	}
+	newLogger := c.clone()
+	newLogger.ctx = ctx
 	return newLogger
 }
 
+// WithCallerSkip returns a copy of the logger that skips n additional
+// stack frames when resolving the call site, for use in wrapper helpers
+func (c *BaseLogger) WithCallerSkip(n int) *BaseLogger {
+	newLogger := c.clone()
+	newLogger.callerSkip += n
+	return newLogger
+}
+
+// clone returns a shallow copy of the logger sharing its handler
+func (c *BaseLogger) clone() *BaseLogger {
+	root := c.getRoot()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+
+	out := &BaseLogger{
+		base:        c.base,
+		levelVar:    c.levelVar,
+		discard:     c.discard,
+		root:        c.root,
+		loggers:     c.loggers,
+		opts:        c.opts,
+		ctx:         c.ctx,
+		name:        c.name,
+		stdout:      c.stdout,
+		stderr:      c.stderr,
+		level:       c.level,
+		addSource:   c.addSource,
+		loggerType:  c.loggerType,
+		consoleOpts: c.consoleOpts,
+		maxMsgLen:   c.maxMsgLen,
+		maxValueLen: c.maxValueLen,
+		maxAttrs:    c.maxAttrs,
+		maxSize:     c.maxSize,
+		flatten:     c.flatten,
+		expand:      c.expand,
+
+		sourceFormat:     c.sourceFormat,
+		sourceTrimPrefix: c.sourceTrimPrefix,
+		sourceFunc:       c.sourceFunc,
+
+		callerSkip: c.callerSkip,
+		badKeys:    c.badKeys,
+		dedup:      c.dedup,
+		dedupMode:  c.dedupMode,
+
+		stackTrace: c.stackTrace,
+		stackLevel: c.stackLevel,
+		stackOff:   c.stackOff,
+		stackDepth: c.stackDepth,
+		stackSkip:  c.stackSkip,
+
+		timeFormat:   c.timeFormat,
+		timeLocation: c.timeLocation,
+
+		hmacKey:      c.hmacKey,
+		keys:         c.keys,
+		replaceAttrs: c.replaceAttrs,
+		defaultAttrs: c.defaultAttrs,
+		withArgs:     c.withArgs,
+		enrichers:    c.enrichers,
+
+		spanExtractor:  c.spanExtractor,
+		sampledLevel:   c.sampledLevel,
+		sampledLevelOn: c.sampledLevelOn,
+
+		requiredAttrs:      c.requiredAttrs,
+		requiredAttrsLevel: c.requiredAttrsLevel,
+		requiredAttrsMode:  c.requiredAttrsMode,
+
+		sequence: c.sequence,
+		seq:      c.seq,
+
+		uptime:    c.uptime,
+		startTime: c.startTime,
+
+		runtimeStats:      c.runtimeStats,
+		runtimeStatsLevel: c.runtimeStatsLevel,
+
+		stream:     c.stream,
+		subs:       c.subs,
+		metricSubs: c.metricSubs,
+		drops:      c.drops,
+
+		recentSize: c.recentSize,
+		recent:     c.recent,
+
+		crashDir: c.crashDir,
+
+		sampleRates:    c.sampleRates,
+		sampleInterval: c.sampleInterval,
+		sampling:       c.sampling,
+
+		breakerThreshold: c.breakerThreshold,
+		breakerWindow:    c.breakerWindow,
+		breakerCooldown:  c.breakerCooldown,
+		breaker:          c.breaker,
+
+		asyncSize: c.asyncSize,
+		asyncOpts: c.asyncOpts,
+		async:     c.async,
+
+		tenants: c.tenants,
+	}
+	out.logger.Store(c.logger.Load())
+	return out
+}
+
 func (c *BaseLogger) WithLoggerType(loggerType string) Logger {
+	root := c.getRoot()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
 	c.loggerType = loggerType
+	c.base = nil
 	c.configureLogger()
 	return c
 }
@@ -102,104 +417,467 @@ func (c *BaseLogger) getRoot() *BaseLogger {
 	return c.root
 }
 
-func (c *BaseLogger) Focus(names ...string) {
-	root := c.getRoot()
-	root.mu.Lock()
-	defer root.mu.Unlock()
+// focusSet is an immutable snapshot of the focused logger names,
+// it is swapped atomically so that focus checks do not take locks
+type focusSet struct {
+	names map[string]bool
+}
 
-	root.focused = true
-	root.focusMap = map[string]bool{}
+// Focus restricts output to the loggers with the given names
+func (c *BaseLogger) Focus(names ...string) {
+	set := &focusSet{names: make(map[string]bool, len(names))}
 	for _, name := range names {
-		root.focusMap[name] = true
+		set.names[name] = true
 	}
+	c.getRoot().focus.Store(set)
+}
 
-	// TODO: Move to configureLogger
-	for _, logger := range root.loggers {
-		logger.configureLogger()
-	}
+// Unfocus restores output for all loggers
+func (c *BaseLogger) Unfocus() {
+	c.getRoot().focus.Store(nil)
+}
 
-	root.configureLogger()
+func (c *BaseLogger) isFocused() bool {
+	set := c.getRoot().focus.Load()
+	return set == nil || set.names[c.name]
 }
 
-func (c *BaseLogger) Unfocus() {
+// GetLogger returns the child logger named name, creating it on first
+// use. Options customize a new child and are ignored when the child
+// exists, they override the configuration it inherits from c without
+// affecting c or its other children, e.g. to write access logs to
+// their own file or to log a subsystem at debug.
+//
+//	access := logger.GetLogger("access", glog.WithOutput(f), glog.WithLoggerTypeJSON())
+//	db := logger.GetLogger("db", glog.WithLevel("debug"))
+//
+// Options that configure the whole tree are ignored here: WithMetrics,
+// WithErrorBurst, WithDropReport, WithControlSocket, WithFlushTimeout,
+// WithExitCodes, WithDevelopment, WithAuditOutput, WithAuditChain and
+// WithAuditChainKey. They and options that fail are reported as
+// warnings on the root logger.
+func (c *BaseLogger) GetLogger(name string, opts ...Option) *BaseLogger {
 	root := c.getRoot()
 	root.mu.Lock()
-	defer root.mu.Unlock()
 
-	root.focused = false
-	root.focusMap = map[string]bool{}
+	if out, ok := c.root.loggers[name]; ok {
+		root.mu.Unlock()
+		return out
+	}
 
-	for _, logger := range root.loggers {
-		logger.configureLogger()
+	out := c.derive(name)
+	// each child samples its own records, tenants share theirs
+	out.sampling = nil
+	if len(opts) > 0 {
+		out.override(opts)
 	}
-	root.configureLogger()
+	out.configureLogger()
+
+	c.root.loggers[name] = out
+	errs := out.errs
+	out.errs = nil
+	root.mu.Unlock()
+
+	for _, err := range errs {
+		root.Warn("glog: option ignored", "logger", name, "error", err)
+	}
+
+	return out
 }
 
-func (c *BaseLogger) isFocused() bool {
+// Lookup returns the child logger named name without creating it
+func (c *BaseLogger) Lookup(name string) (*BaseLogger, bool) {
 	root := c.getRoot()
 	root.mu.RLock()
 	defer root.mu.RUnlock()
 
-	if !root.focused {
-		return true
-	}
-
-	return root.focusMap[c.name]
+	out, ok := root.loggers[name]
+	return out, ok
 }
 
-func (c *BaseLogger) GetLogger(name string) *BaseLogger {
+// LoggerNames returns the sorted names of the child loggers
+func (c *BaseLogger) LoggerNames() []string {
 	root := c.getRoot()
-	root.mu.Lock()
-	defer root.mu.Unlock()
-
-	if out, ok := c.root.loggers[name]; ok {
-		return out
+	root.mu.RLock()
+	names := make([]string, 0, len(root.loggers))
+	for name := range root.loggers {
+		names = append(names, name)
 	}
+	root.mu.RUnlock()
 
-	out := NewLogger()
-	out.root = root
+	slices.Sort(names)
+	return names
+}
+
+// derive returns an unconfigured logger named name that inherits the
+// configuration of c, the caller must hold the root lock
+func (c *BaseLogger) derive(name string) *BaseLogger {
+	out := &BaseLogger{
+		ctx:      context.Background(),
+		loggers:  map[string]*BaseLogger{},
+		levelVar: &slog.LevelVar{},
+	}
+	out.root = c.getRoot()
 	out.name = name
-	out.level = c.level
+	out.base = c.base
+	out.discard = c.discard
+	out.levelVar.Set(c.levelVar.Level())
 	out.addSource = c.addSource
+	out.stdout = c.stdout
+	out.stderr = c.stderr
 	out.loggerType = c.loggerType
+	out.consoleOpts = c.consoleOpts
+	out.maxMsgLen = c.maxMsgLen
+	out.maxValueLen = c.maxValueLen
+	out.maxAttrs = c.maxAttrs
+	out.maxSize = c.maxSize
+	out.flatten = c.flatten
+	out.expand = c.expand
+	out.sourceFormat = c.sourceFormat
+	out.sourceTrimPrefix = c.sourceTrimPrefix
+	out.sourceFunc = c.sourceFunc
+	out.callerSkip = c.callerSkip
+	out.badKeys = c.badKeys
+	out.dedup = c.dedup
+	out.dedupMode = c.dedupMode
+	out.stackTrace = c.stackTrace
+	out.stackLevel = c.stackLevel
+	out.stackOff = c.stackOff
+	out.stackDepth = c.stackDepth
+	out.stackSkip = c.stackSkip
+	out.timeFormat = c.timeFormat
+	out.timeLocation = c.timeLocation
+	out.keys = c.keys
+	out.hmacKey = c.hmacKey
+	out.spanExtractor = c.spanExtractor
+	out.sampledLevel = c.sampledLevel
+	out.sampledLevelOn = c.sampledLevelOn
+	out.replaceAttrs = c.replaceAttrs
+	out.defaultAttrs = c.defaultAttrs
+	out.enrichers = c.enrichers
+	out.requiredAttrs = c.requiredAttrs
+	out.requiredAttrsLevel = c.requiredAttrsLevel
+	out.requiredAttrsMode = c.requiredAttrsMode
+	out.sequence = c.sequence
+	out.uptime = c.uptime
+	out.startTime = c.startTime
+	out.runtimeStats = c.runtimeStats
+	out.runtimeStatsLevel = c.runtimeStatsLevel
+	out.stream = c.stream
+	out.subs = c.subs
+	out.metricSubs = c.metricSubs
+	out.drops = c.drops
+	out.recentSize = c.recentSize
+	out.crashDir = c.crashDir
+	out.sampleRates = c.sampleRates
+	out.sampleInterval = c.sampleInterval
+	out.sampling = c.sampling
+	out.breakerThreshold = c.breakerThreshold
+	out.breakerWindow = c.breakerWindow
+	out.breakerCooldown = c.breakerCooldown
+	out.asyncSize = c.asyncSize
+	out.asyncOpts = c.asyncOpts
+	out.async = c.async
+	out.tenants = c.tenants
 
-	out.configureLogger()
+	return out
+}
 
-	c.root.loggers[name] = out
+// override applies opts to a new child. The child keeps its name and
+// only builds its own output handler when opts change the output
+// configuration. Outputs opened by opts are owned by the root so Close
+// releases them.
+func (c *BaseLogger) override(opts []Option) {
+	name := c.name
+	output := c.outputConfig()
+	c.level = ""
+	// tenant overrides are the child's own, the maps are copied so
+	// options do not change them for the rest of the tree
+	c.tenants = c.tenants.clone()
+
+	c.overriding = true
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.overriding = false
 
-	return out
+	c.name = name
+	if c.level != "" {
+		c.levelVar.Set(getLevel(c.level))
+	}
+	if !c.outputConfig().equal(output) {
+		c.base = nil
+	}
+
+	root := c.getRoot()
+	root.closers = append(root.closers, c.closers...)
+	c.closers = nil
+}
+
+// treeOption reports whether the option name, which configures the
+// whole tree, may be applied to c. Options passed to GetLogger may
+// not, they are recorded as errors instead.
+func (c *BaseLogger) treeOption(name string) bool {
+	if !c.overriding {
+		return true
+	}
+	c.errs = append(c.errs, fmt.Errorf("glog: %s configures the whole logger tree, pass it to NewLogger", name))
+	return false
 }
 
-// With returns a Logger that includes the given attributes
-// in each subsequent log output.
+// outputConfig holds the fields read by newBaseHandler
+type outputConfig struct {
+	stdout           io.Writer
+	stderr           io.Writer
+	loggerType       string
+	keys             fieldKeys
+	addSource        bool
+	sourceFormat     SourceFormat
+	sourceTrimPrefix string
+	sourceFunc       bool
+	timeFormat       string
+	timeLocation     *time.Location
+	flatten          bool
+	expand           bool
+	maxAttrs         int
+	maxSize          int
+	maxMsgLen        int
+	maxValueLen      int
+	stream           *LogStream
+	asyncSize        int
+	asyncOpts        []AsyncOption
+	consoleOpts      []ColorConsoleOption
+	replaceAttrs     []func(groups []string, a slog.Attr) slog.Attr
+	hmacKey          []byte
+}
+
+func (c *BaseLogger) outputConfig() outputConfig {
+	return outputConfig{
+		stdout:           c.stdout,
+		stderr:           c.stderr,
+		loggerType:       c.loggerType,
+		keys:             c.keys,
+		addSource:        c.addSource,
+		sourceFormat:     c.sourceFormat,
+		sourceTrimPrefix: c.sourceTrimPrefix,
+		sourceFunc:       c.sourceFunc,
+		timeFormat:       c.timeFormat,
+		timeLocation:     c.timeLocation,
+		flatten:          c.flatten,
+		expand:           c.expand,
+		maxAttrs:         c.maxAttrs,
+		maxSize:          c.maxSize,
+		maxMsgLen:        c.maxMsgLen,
+		maxValueLen:      c.maxValueLen,
+		stream:           c.stream,
+		asyncSize:        c.asyncSize,
+		asyncOpts:        c.asyncOpts,
+		consoleOpts:      c.consoleOpts,
+		replaceAttrs:     c.replaceAttrs,
+		hmacKey:          c.hmacKey,
+	}
+}
+
+// equal reports whether both configurations build the same handler,
+// options only ever append to the slices so their length and backing
+// array tell whether they changed
+func (o outputConfig) equal(p outputConfig) bool {
+	return sameWriter(o.stdout, p.stdout) &&
+		sameWriter(o.stderr, p.stderr) &&
+		o.loggerType == p.loggerType &&
+		o.keys == p.keys &&
+		o.addSource == p.addSource &&
+		o.sourceFormat == p.sourceFormat &&
+		o.sourceTrimPrefix == p.sourceTrimPrefix &&
+		o.sourceFunc == p.sourceFunc &&
+		o.timeFormat == p.timeFormat &&
+		o.timeLocation == p.timeLocation &&
+		o.flatten == p.flatten &&
+		o.expand == p.expand &&
+		o.maxAttrs == p.maxAttrs &&
+		o.maxSize == p.maxSize &&
+		o.maxMsgLen == p.maxMsgLen &&
+		o.maxValueLen == p.maxValueLen &&
+		o.stream == p.stream &&
+		o.asyncSize == p.asyncSize &&
+		sameSlice(o.asyncOpts, p.asyncOpts) &&
+		sameSlice(o.consoleOpts, p.consoleOpts) &&
+		sameSlice(o.replaceAttrs, p.replaceAttrs) &&
+		sameSlice(o.hmacKey, p.hmacKey)
+}
+
+func sameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// sameWriter compares writers without panicking on writers whose
+// dynamic type is not comparable
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// With returns a copy of the logger that includes the given
+// attributes in each subsequent log output. The receiver is not
+// modified, callers must use the returned logger. The copy shares the
+// level of c, see WithLevel.
 func (c *BaseLogger) With(args ...any) *BaseLogger {
 	if len(args) == 0 {
 		return c
 	}
-	c.logger = c.logger.With(argsToAttrSlice(args)...)
-	return c
+	attrs := argsToAttrSlice(args)
+	if c.badKeys != BadKeyIgnore {
+		var pcs [1]uintptr
+		runtime.Callers(2, pcs[:])
+		c.checkBadKeys(c.ctx, c.logger.Load(), pcs[0], "", argsToAttrs(args))
+	}
+
+	out := c.clone()
+	out.withArgs = append(slices.Clone(c.withArgs), attrs...)
+	out.logger.Store(c.logger.Load().With(attrs...))
+	return out
+}
+
+// Enabled reports whether a record at level would be logged, use it to
+// guard expensive argument construction
+func (c *BaseLogger) Enabled(level slog.Level) bool {
+	return c.logger.Load().Enabled(c.ctx, level)
+}
+
+// IsTrace reports whether trace records are logged
+func (c *BaseLogger) IsTrace() bool {
+	return c.Enabled(LevelTrace)
+}
+
+// IsDebug reports whether debug records are logged
+func (c *BaseLogger) IsDebug() bool {
+	return c.Enabled(slog.LevelDebug)
 }
 
 func (c *BaseLogger) Trace(msg string, args ...any) {
-	c.logger.Log(c.ctx, LevelTrace, msg, args...)
+	c.log(c.ctx, 0, LevelTrace, msg, args...)
 }
 
 func (c *BaseLogger) Debug(msg string, args ...any) {
-	c.logger.Log(c.ctx, slog.LevelDebug, msg, args...)
+	c.log(c.ctx, 0, slog.LevelDebug, msg, args...)
 }
 
 func (c *BaseLogger) Info(msg string, args ...any) {
-	c.logger.Log(c.ctx, slog.LevelInfo, msg, args...)
+	c.log(c.ctx, 0, slog.LevelInfo, msg, args...)
 }
 
 func (c *BaseLogger) Warn(msg string, args ...any) {
-	c.logger.Log(c.ctx, slog.LevelWarn, msg, args...)
+	c.log(c.ctx, 0, slog.LevelWarn, msg, args...)
 }
 
 func (c *BaseLogger) Error(msg string, args ...any) {
+	c.logError(c.ctx, slog.LevelError, msg, args...)
+}
+
+// DPanic logs at DPanic level like Error and, in development mode, see
+// WithDevelopment, panics with msg after flushing
+func (c *BaseLogger) DPanic(msg string, args ...any) {
+	c.logError(c.ctx, LevelDPanic, msg, args...)
+
+	if c.getRoot().development {
+		c.getRoot().Flush()
+		panic(msg)
+	}
+}
+
+// Panic logs at Panic level like Error, flushes and panics with msg
+func (c *BaseLogger) Panic(msg string, args ...any) {
+	c.logError(c.ctx, LevelPanic, msg, args...)
+
+	c.getRoot().Flush()
+	panic(msg)
+}
+
+// TraceContext logs at trace level with ctx instead of the logger's
+// context, avoiding a WithContext copy per request
+func (c *BaseLogger) TraceContext(ctx context.Context, msg string, args ...any) {
+	c.log(ctx, 0, LevelTrace, msg, args...)
+}
+
+// DebugContext logs at debug level with ctx
+func (c *BaseLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	c.log(ctx, 0, slog.LevelDebug, msg, args...)
+}
+
+// InfoContext logs at info level with ctx
+func (c *BaseLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	c.log(ctx, 0, slog.LevelInfo, msg, args...)
+}
+
+// WarnContext logs at warn level with ctx
+func (c *BaseLogger) WarnContext(ctx context.Context, msg string, args ...any) {
+	c.log(ctx, 0, slog.LevelWarn, msg, args...)
+}
+
+// ErrorContext logs at error level with ctx, error details are
+// added like in Error
+func (c *BaseLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	c.logError(ctx, slog.LevelError, msg, args...)
+}
+
+// callerDepth is the number of frames between runtime.Callers in log
+// and the public method that was invoked by the user
+const callerDepth = 3
+
+// log creates the record with the caller's PC so source info points
+// at the call site instead of this package. skip is the number of
+// frames between log and the public method, zero when called directly.
+func (c *BaseLogger) log(ctx context.Context, skip int, level slog.Level, msg string, args ...any) {
+	logger := c.logger.Load()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(callerDepth+skip+c.callerSkip, pcs[:])
+
+	attrs := argsToAttrs(args)
+	c.checkBadKeys(ctx, logger, pcs[0], msg, attrs)
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+
+	if c.stackTrace && !c.stackOff && level >= c.stackLevel && !hasStackArg(args) {
+		// skip runtime.Callers, getStackTrace and log
+		r.AddAttrs(slog.Any(StackKey, c.getStackTrace(callerDepth+1+skip)))
+	}
+
+	c.handle(ctx, logger, r)
+}
+
+func hasStackArg(args []any) bool {
+	for _, arg := range args {
+		if a, ok := arg.(slog.Attr); ok && a.Key == StackKey {
+			return true
+		}
+	}
+	return false
+}
+
+// logError logs at level, Error or above, enriching args with error
+// details and stack trace. Like log, it must be called directly from a
+// public logging method.
+func (c *BaseLogger) logError(ctx context.Context, level slog.Level, msg string, args ...any) {
+	// check before scanning args and capturing the stack so that
+	// filtered calls do not allocate
+	if !c.logger.Load().Enabled(ctx, level) {
+		return
+	}
+
 	err, nargs := findError(args)
 	if err == nil {
-		c.logger.Log(c.ctx, slog.LevelError, msg, nargs...)
+		c.log(ctx, 1, level, msg, nargs...)
 		return
 	}
 
@@ -213,46 +891,79 @@ func (c *BaseLogger) Error(msg string, args ...any) {
 		dargs = append(dargs, slog.Any("status_code", ce.Status()))
 	}
 
-	root := err
-	for {
-		unwrapped := errors.Unwrap(root)
-		if unwrapped == nil {
-			break
-		}
-		root = unwrapped
+	chain := errorChain(err)
+	if len(chain) > 1 {
+		dargs = append(dargs, slog.Any("root_error", chain[len(chain)-1]))
+		dargs = append(dargs, errorChainAttr(chain))
 	}
 
-	if root != err {
-		dargs = append(dargs, slog.Any("root_error", root))
+	attrs, pcs := errorDetails(chain)
+	for _, attr := range attrs {
+		dargs = append(dargs, attr)
 	}
 
-	dargs = append(dargs, slog.Any("error", err))
+	dargs = append(dargs, slog.Any(ErrorKey, err))
 
-	stack := getStackTrace(4)
-
-	dargs = append(dargs, slog.Any("stack", stack))
+	if !c.stackOff {
+		var stack string
+		if pcs != nil {
+			stack = formatStack(pcs)
+		} else {
+			// skip runtime.Callers, getStackTrace and logError
+			stack = c.getStackTrace(callerDepth + 1)
+		}
+		dargs = append(dargs, slog.Any(StackKey, stack))
+	}
 
-	c.logger.Log(c.ctx, slog.LevelError, msg, dargs...)
+	c.log(ctx, 1, level, msg, dargs...)
 }
 
 func (c *BaseLogger) Fatal(msg string, args ...any) {
-	c.Error(msg, args...)
+	c.logError(c.ctx, slog.LevelError, msg, args...)
 
-	code := 1
-	if err, _ := findError(args); err != nil {
-		if ce, ok := err.(coder); ok {
-			code = ce.Code()
-		}
+	if c.crashDir != "" {
+		// skip runtime.Callers, crashStack and Fatal
+		c.writeCrash(msg, args, "", crashStack(3+c.callerSkip))
 	}
 
-	// NOTE: might need to come up with a way to flush any async logs, maybe
+	err, _ := findError(args)
+	code := c.getRoot().exitCode(err)
+
+	// the whole tree, a child alone would miss the root's sinks
+	c.getRoot().Flush()
 	os.Exit(code)
 }
 
+// exitCode returns the process exit code of Fatal for err, see
+// WithExitCodes. Without a mapping the code of err is used as is.
+func (c *BaseLogger) exitCode(err error) int {
+	ce, ok := err.(coder)
+	if c.exitCodes == nil {
+		if ok {
+			return ce.Code()
+		}
+		return 1
+	}
+
+	if ok {
+		if code, found := c.exitCodes[ce.Code()]; found {
+			return code
+		}
+	}
+	return c.exitDefault
+}
+
 func findError(args []any) (errFound error, remaining []any) {
 	remaining = make([]any, 0, len(args))
 
 	for i := 0; i < len(args); i++ {
+		if a, ok := args[i].(slog.Attr); ok && a.Key == ErrorKey && errFound == nil {
+			if e, ok := a.Value.Any().(error); ok && e != nil {
+				errFound = e
+				continue
+			}
+		}
+
 		if key, ok := args[i].(string); ok && key == "error" && i+1 < len(args) {
 			if errVal, ok := args[i+1].(error); ok && errVal != nil {
 				remaining = append(remaining, args[i], args[i+1])
@@ -270,51 +981,232 @@ func findError(args []any) (errFound error, remaining []any) {
 	return errFound, remaining
 }
 
+// minLevel lets every record through the base handler, levels
+// are enforced per logger by a LevelHandler
+const minLevel = slog.Level(math.MinInt)
+
+// configureLogger builds the handler chain of the logger. The base
+// handler is only built when missing so that children reuse it.
 func (c *BaseLogger) configureLogger() {
-	c.opts = &slog.HandlerOptions{
-		Level:     getLevel(c.level),
-		AddSource: c.addSource,
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-
-			// Replace msg key with message string
-			if a.Key == slog.TimeKey {
-				a.Key = "ts"
-				return a
+	if c.levelVar == nil {
+		c.levelVar = &slog.LevelVar{}
+		c.levelVar.Set(getLevel(c.level))
+	}
+
+	if c.discard {
+		c.logger.Store(slog.New(discardHandler{}))
+		c.sink.Store(slog.New(discardHandler{}))
+		return
+	}
+
+	if c.base == nil {
+		c.base = c.newBaseHandler()
+	}
+	c.sink.Store(slog.New(c.base))
+
+	handler := c.base
+
+	if c.dedup {
+		handler = NewDedupHandler(handler, c.dedupMode)
+	}
+
+	// keep the buffer across reconfiguration, children get their own
+	if c.recentSize > 0 && c.recent == nil {
+		c.recent = newRecentBuffer(c.recentSize)
+	}
+
+	handler = newSubscribeHandler(handler, c.subs, c.recent, c.name)
+
+	enrichers := append([]Enricher{ContextEnricher()}, c.enrichers...)
+	if c.sequence {
+		// keep the counter across reconfiguration, children get their own
+		if c.seq == nil {
+			c.seq = &atomic.Uint64{}
+		}
+		enrichers = append(enrichers, SequenceEnricher(c.seq))
+	}
+
+	if c.uptime {
+		enrichers = append(enrichers, UptimeEnricher(c.startTime))
+	}
+
+	if c.runtimeStats {
+		enrichers = append(enrichers, RuntimeStatsEnricher(c.runtimeStatsLevel))
+	}
+
+	handler = NewEnrichHandler(handler, enrichers...)
+
+	if len(c.requiredAttrs) > 0 {
+		handler = NewRequiredAttrsHandler(handler, c.requiredAttrsLevel, c.requiredAttrsMode, c.requiredAttrs...)
+	}
+
+	if c.breakerThreshold > 0 {
+		// keep the state across reconfiguration, children get their own
+		if c.breaker == nil {
+			name := c.name
+			if name == "" {
+				name = "root"
 			}
+			c.breaker = newBreakerState(name, c.breakerThreshold, c.breakerWindow, c.breakerCooldown, c.drops)
+		}
+		handler = newBreakerHandler(handler, c.breaker)
+	}
 
-			if a.Key == slog.LevelKey {
-				level := a.Value.Any().(slog.Level)
-				levelLabel, exists := CustomLevels[level]
-				if !exists {
-					levelLabel = level.String()
-				}
+	// the state outlives rebuilds and is shared with copies and
+	// tenants, so SetSampling reaches them without a rebuild
+	if c.sampling == nil {
+		c.sampling = newSampleState(c.sampleRates, c.sampleInterval, c.drops)
+	}
+	handler = &SampleHandler{handler: handler, state: c.sampling}
+
+	if c.metricSubs.active() {
+		handler = newSubscribeHandler(handler, c.metricSubs, nil, c.name)
+	}
+
+	handler = NewFocusFilterHandler(handler, c)
+	handler = NewDeferredHandler(handler)
+	level := &LevelHandler{level: c.levelVar, handler: handler}
+	if c.sampledLevelOn {
+		level.sampled = &sampledSpanLevel{level: c.sampledLevel, extract: c.extractSpan()}
+	}
+	handler = level
 
-				a.Value = slog.StringValue(strings.ToLower(levelLabel))
+	if c.name != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String(LoggerKey, c.name)})
+	}
+
+	if len(c.defaultAttrs) > 0 {
+		handler = handler.WithAttrs(c.defaultAttrs)
+	}
+
+	c.logger.Store(slog.New(handler))
+}
+
+// newBaseHandler builds the output handler with the record
+// formatting options of the logger
+func (c *BaseLogger) newBaseHandler() slog.Handler {
+	// the pretty handler relies on the default keys to lay out records
+	keys := c.keys
+	if c.loggerType == LoggerTypePretty {
+		keys = defaultFieldKeys()
+	}
+
+	builtin := func(groups []string, a slog.Attr) slog.Attr {
+
+		// Replace msg key with message string
+		if a.Key == slog.TimeKey {
+			a.Key = keys.time
+			a.Value = formatTime(a.Value, c.timeFormat, c.timeLocation)
+			return a
+		}
+
+		if a.Key == slog.LevelKey {
+			level := a.Value.Any().(slog.Level)
+			levelLabel, exists := CustomLevels[level]
+			if !exists {
+				levelLabel = level.String()
 			}
+
+			a.Key = keys.level
+			a.Value = slog.StringValue(strings.ToLower(levelLabel))
 			return a
-		},
+		}
+
+		if a.Key == slog.SourceKey && len(groups) == 0 {
+			if src, ok := a.Value.Any().(*slog.Source); ok {
+				a.Value = formatSource(src, c.sourceFormat, c.sourceTrimPrefix, c.sourceFunc)
+			}
+		}
+
+		if len(groups) == 0 {
+			a.Key = keys.rename(a.Key)
+		}
+		return a
 	}
 
-	var handler slog.Handler
+	replaceAttr := builtin
+	if len(c.replaceAttrs) > 0 {
+		fns := c.replaceAttrs
+		replaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			a = builtin(groups, a)
+			for _, fn := range fns {
+				if a.Equal(slog.Attr{}) {
+					return a
+				}
+				a = fn(groups, a)
+			}
+			return a
+		}
+	}
+
+	c.opts = &slog.HandlerOptions{
+		Level:       minLevel,
+		AddSource:   c.addSource,
+		ReplaceAttr: replaceAttr,
+	}
+
+	handler := c.newFormatHandler(c.stdout)
+	if c.stderr != nil {
+		handler = NewSplitHandler(slog.LevelWarn, handler, c.newFormatHandler(c.stderr))
+	}
+
+	if c.flatten {
+		handler = NewFlattenHandler(handler)
+	}
+
+	if c.expand {
+		handler = NewExpandHandler(handler)
+	}
+
+	// the guard runs after value truncation
+	if c.maxAttrs > 0 || c.maxSize > 0 {
+		handler = NewSizeGuardHandler(handler, c.maxAttrs, c.maxSize)
+	}
+
+	if c.maxMsgLen > 0 || c.maxValueLen > 0 {
+		handler = NewTruncateHandler(handler, c.maxMsgLen, c.maxValueLen)
+	}
+
+	if c.stream != nil {
+		handler = NewStreamHandler(handler, c.stream, c.opts)
+	}
+
+	prev := c.async
+	c.async = nil
+	if c.asyncSize > 0 {
+		if prev != nil && prev.queue.size == c.asyncSize && sameSlice(prev.queue.opts, c.asyncOpts) {
+			// keep the queue and its goroutine, queued records carry
+			// the handler they were logged with
+			c.async = &AsyncHandler{handler: handler, queue: prev.queue}
+		} else {
+			// an inherited queue with another configuration stays
+			// with the logger that owns it
+			c.async = NewAsyncHandler(handler, c.asyncSize, c.asyncOpts...)
+			c.async.queue.drops = c.drops
+		}
+		handler = c.async
+	}
+
+	return handler
+}
+
+// newFormatHandler returns the handler of the logger type writing to out
+func (c *BaseLogger) newFormatHandler(out io.Writer) slog.Handler {
+	if len(c.hmacKey) > 0 {
+		out = NewHMACWriter(out, c.hmacKey)
+	}
 
 	switch c.loggerType {
 	case LoggerTypeConsole:
-		handler = slog.NewTextHandler(c.stdout, c.opts)
+		return slog.NewTextHandler(out, c.opts)
 	case LoggerTypePretty:
-		handler = NewColorConsoleHandler(c.stdout, c.opts)
+		consoleOpts := append([]ColorConsoleOption{WithColorConsoleLocation(c.timeLocation)}, c.consoleOpts...)
+		return NewColorConsoleHandler(out, c.opts, consoleOpts...)
 	case LoggerTypeJSON:
-		handler = slog.NewJSONHandler(c.stdout, c.opts)
+		return slog.NewJSONHandler(out, c.opts)
 	default:
-		handler = slog.NewJSONHandler(c.stdout, c.opts)
+		return slog.NewJSONHandler(out, c.opts)
 	}
-
-	handler = NewFocusFilterHandler(handler, c)
-
-	if c.name != "" {
-		handler = handler.WithAttrs([]slog.Attr{slog.String("logger", c.name)})
-	}
-
-	c.logger = slog.New(handler)
 }
 
 func NewFocusFilterHandler(handler slog.Handler, logger *BaseLogger) slog.Handler {
@@ -358,27 +1250,54 @@ func (h *FocusFilterHandler) WithGroup(name string) slog.Handler {
 }
 
 func getLevel(l string) slog.Level {
-	switch strings.ToUpper(l) {
-	case "ERROR":
-		return slog.LevelError
-	case "WARN":
-		return slog.LevelWarn
-	case "INFO":
-		return slog.LevelInfo
-	case "DEBUG":
-		return slog.LevelDebug
-	case "TRACE":
-		return LevelTrace
+	level, _ := ParseLevel(l)
+	return level
+}
+
+// ParseLevel returns the level for name, e.g. "debug" or "WARN".
+// Unknown names return LevelInfo and an error.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToUpper(name) {
+	case Fatal:
+		return LevelFatal, nil
+	case Panic:
+		return LevelPanic, nil
+	case DPanic:
+		return LevelDPanic, nil
+	case Error:
+		return slog.LevelError, nil
+	case Warn:
+		return slog.LevelWarn, nil
+	case Info:
+		return slog.LevelInfo, nil
+	case Debug:
+		return slog.LevelDebug, nil
+	case Trace:
+		return LevelTrace, nil
 	default:
-		return slog.LevelInfo
+		return slog.LevelInfo, fmt.Errorf("glog: unknown level %q", name)
 	}
 }
 
-func getStackTrace(skip int) string {
-	const depth = 32
+// defaultStackDepth is the number of frames captured in stack traces
+// unless set with WithStackDepth
+const defaultStackDepth = 32
+
+// getStackTrace captures the stack skipping skip frames plus the caller
+// and stack skip configured on the logger
+func (c *BaseLogger) getStackTrace(skip int) string {
+	depth := c.stackDepth
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
 	pcs := make([]uintptr, depth)
-	n := runtime.Callers(skip, pcs)
-	pcs = pcs[:n]
+	n := runtime.Callers(skip+c.callerSkip+c.stackSkip, pcs)
+	return formatStack(pcs[:n])
+}
+
+// formatStack renders pcs as function and file:line pairs, one frame
+// per two lines
+func formatStack(pcs []uintptr) string {
 	frames := runtime.CallersFrames(pcs)
 
 	var sb strings.Builder
@@ -0,0 +1,209 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newDisabledLogger() *BaseLogger {
+	return NewLogger(WithOutput(io.Discard), WithLevel(Fatal))
+}
+
+// the args are values that box without allocating, boxing other
+// values allocates in the caller before the logger is reached
+func TestDisabledLevelsDoNotAllocate(t *testing.T) {
+	l := newDisabledLogger()
+	err := errors.New("boom")
+	name := "x"
+
+	tests := []struct {
+		name string
+		log  func()
+	}{
+		{"debug", func() { l.Debug("msg", "id", 1, "name", name) }},
+		{"info", func() { l.Info("msg", "id", 1, "name", name) }},
+		{"error", func() { l.Error("msg", "id", 1, err) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.log); allocs != 0 {
+				t.Fatalf("got %v allocs per call, want 0", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkDisabledDebug(b *testing.B) {
+	l := newDisabledLogger()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("msg", "id", 1, "name", "x")
+	}
+}
+
+func BenchmarkDisabledInfo(b *testing.B) {
+	l := newDisabledLogger()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("msg", "id", 1, "name", "x")
+	}
+}
+
+func BenchmarkDisabledError(b *testing.B) {
+	l := newDisabledLogger()
+	err := errors.New("boom")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Error("msg", "id", 1, err)
+	}
+}
+
+// TestConcurrentConfiguration is meant to run with -race, it changes
+// the configuration while other goroutines log
+func TestConcurrentConfiguration(t *testing.T) {
+	l := NewLogger(WithOutput(io.Discard))
+	types := []string{LoggerTypeJSON, LoggerTypeConsole, LoggerTypePretty}
+	names := []string{"a", "b", "c"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				name := names[(g+i)%len(names)]
+				child := l.GetLogger(name)
+
+				switch i % 6 {
+				case 0:
+					child.WithLevel(Debug)
+				case 1:
+					child.WithLoggerType(types[i%len(types)])
+				case 2:
+					l.Focus(name)
+				case 3:
+					l.Unfocus()
+				case 4:
+					l.WithLevel(Info)
+				}
+
+				child.With("i", i).Info("msg", "g", g)
+				child.WithContext(context.Background()).(*BaseLogger).Debug("msg")
+				l.Warn("root")
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestWithDoesNotModifyReceiver(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf))
+
+	l.With("request", "r1")
+	l.Info("x")
+
+	if strings.Contains(buf.String(), "r1") {
+		t.Fatalf("With modified the receiver: %s", buf.String())
+	}
+}
+
+func TestCopiesShareLevel(t *testing.T) {
+	l := NewLogger(WithOutput(io.Discard), WithLevel(Info))
+	copied := l.With("k", "v")
+
+	copied.WithLevel(Debug)
+	if l.Level() != slog.LevelDebug {
+		t.Fatalf("parent level = %v, want copies to share it", l.Level())
+	}
+
+	l.WithLevel(Warn)
+	if copied.Level() != slog.LevelWarn {
+		t.Fatalf("copy level = %v, want copies to share it", copied.Level())
+	}
+}
+
+func TestGetLoggerTreeOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"metrics", WithMetrics(NewMetrics())},
+		{"error burst", WithErrorBurst(1, time.Minute, nil, func(Burst) {})},
+		{"drop report", WithDropReport(time.Second)},
+		{"control socket", WithControlSocket("/nonexistent/ctl.sock")},
+		{"flush timeout", WithFlushTimeout(time.Second)},
+		{"exit codes", WithExitCodes(map[int]int{1: 2}, 3)},
+		{"development", WithDevelopment()},
+		{"audit output", WithAuditOutput(io.Discard)},
+		{"audit chain", WithAuditChain(2)},
+		{"audit chain key", WithAuditChainKey([]byte("k"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(WithOutput(&buf))
+			subs := l.subs.count.Load() + l.metricSubs.count.Load()
+
+			child := l.GetLogger("child", tt.opt)
+			if child.flushTimeout != 0 || child.exitCodes != nil || child.development ||
+				child.auditOut != nil || child.auditChain {
+				t.Fatal("root only option was applied to the child")
+			}
+
+			if l.subs.count.Load()+l.metricSubs.count.Load() != subs || l.drops.interval != 0 || l.controlPath != "" {
+				t.Fatal("child option changed the tree configuration")
+			}
+			if !strings.Contains(buf.String(), "configures the whole logger tree") {
+				t.Fatalf("ignored option was not reported: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestGetLoggerTenantOptions(t *testing.T) {
+	var rootSink, childSink bytes.Buffer
+	l := NewLogger(WithOutput(io.Discard), WithTenantSink("acme", &rootSink))
+	child := l.GetLogger("child", WithTenantSink("acme", &childSink), WithTenantLevel("acme", Debug))
+
+	l.ForTenant("acme").Debug("root debug")
+	l.ForTenant("acme").Info("root")
+	child.ForTenant("acme").Debug("child")
+
+	if got := rootSink.String(); !strings.Contains(got, "root") || strings.Contains(got, "child") || strings.Contains(got, "root debug") {
+		t.Fatalf("root tenant output = %q", got)
+	}
+	if got := childSink.String(); !strings.Contains(got, "child") {
+		t.Fatalf("child tenant output = %q", got)
+	}
+}
+
+func TestForTenantOnCopies(t *testing.T) {
+	sink := &flushWriter{}
+	l := NewLogger(WithOutput(io.Discard), WithTenantSink("acme", sink))
+
+	l.With("request", "r1").ForTenant("acme").Info("copy")
+	l.WithContext(context.Background()).(*BaseLogger).ForTenant("acme")
+	if len(l.tenantLoggers) != 1 {
+		t.Fatalf("copies cached %d tenant loggers on the root, want 1", len(l.tenantLoggers))
+	}
+
+	got := sink.String()
+	if !strings.Contains(got, `"request":"r1"`) || !strings.Contains(got, `"tenant":"acme"`) {
+		t.Fatalf("tenant of a copy lost attributes: %s", got)
+	}
+
+	l.Flush()
+	if !sink.wasFlushed() {
+		t.Fatal("Flush missed the sink of a tenant made from a copy")
+	}
+}
@@ -0,0 +1,223 @@
+package glog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// DefaultBuckets are the histogram buckets used when none are given,
+// suited to durations in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricName is the grammar of Prometheus metric names
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Metrics derives counters and histograms from log records, turning
+// events that are already logged into metrics. Attach it to a logger
+// tree with WithMetrics and mount it on a mux to expose the values in
+// the Prometheus text format:
+//
+//	m := glog.NewMetrics()
+//	m.CountWhere("payment_failed_total", glog.MessageIs("payment failed"))
+//	m.HistogramWhere("request_duration_seconds", glog.MessageIs("request done"), "duration", nil)
+//	logger := glog.NewLogger(glog.WithMetrics(m))
+//	mux.Handle("/metrics", m)
+//
+// Rules with an invalid or already used name are not added, Err
+// reports them and so does NewLoggerE for rules added before it.
+type Metrics struct {
+	mu         sync.Mutex
+	counters   []*metricCounter
+	histograms []*metricHistogram
+	// series holds the names written by WriteTo
+	series map[string]bool
+	errs   []error
+}
+
+type metricCounter struct {
+	name   string
+	filter RecordFilter
+	value  uint64
+}
+
+type metricHistogram struct {
+	name    string
+	filter  RecordFilter
+	attr    string
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Err returns the rules that could not be added
+func (m *Metrics) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return errors.Join(m.errs...)
+}
+
+// register reserves the series names of the metric name, histograms
+// also write the _bucket, _sum and _count series
+func (m *Metrics) register(name string, suffixes ...string) bool {
+	if !metricName.MatchString(name) {
+		m.errs = append(m.errs, fmt.Errorf("glog: invalid metric name %q", name))
+		return false
+	}
+	names := []string{name}
+	for _, suffix := range suffixes {
+		names = append(names, name+suffix)
+	}
+	for _, n := range names {
+		if m.series[n] {
+			m.errs = append(m.errs, fmt.Errorf("glog: duplicate metric name %q", n))
+			return false
+		}
+	}
+	if m.series == nil {
+		m.series = map[string]bool{}
+	}
+	for _, n := range names {
+		m.series[n] = true
+	}
+	return true
+}
+
+// MessageIs selects records with message msg
+func MessageIs(msg string) RecordFilter {
+	return func(r Record) bool {
+		return r.Message == msg
+	}
+}
+
+// CountWhere increments the counter name for every record that
+// matches filter
+func (m *Metrics) CountWhere(name string, filter RecordFilter) *Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.register(name) {
+		m.counters = append(m.counters, &metricCounter{name: name, filter: filter})
+	}
+	return m
+}
+
+// HistogramWhere observes the numeric value of attr, dotted keys address
+// nested groups, for every record that matches filter. Durations are
+// observed in seconds. Nil buckets use DefaultBuckets.
+func (m *Metrics) HistogramWhere(name string, filter RecordFilter, attr string, buckets []float64) *Metrics {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.register(name, "_bucket", "_sum", "_count") {
+		return m
+	}
+	m.histograms = append(m.histograms, &metricHistogram{
+		name:    name,
+		filter:  filter,
+		attr:    attr,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	})
+	return m
+}
+
+// observe applies the rules to rec
+func (m *Metrics) observe(rec Record) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.counters {
+		if c.filter == nil || c.filter(rec) {
+			c.value++
+		}
+	}
+
+	for _, h := range m.histograms {
+		if h.filter != nil && !h.filter(rec) {
+			continue
+		}
+		v, ok := rec.Attr(h.attr)
+		if !ok {
+			continue
+		}
+		f, ok := metricValue(v)
+		if !ok {
+			continue
+		}
+		for i, le := range h.buckets {
+			if f <= le {
+				h.counts[i]++
+			}
+		}
+		h.sum += f
+		h.count++
+	}
+}
+
+// metricValue converts numeric values and durations, in seconds, to float64
+func metricValue(v slog.Value) (float64, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64()), true
+	case slog.KindUint64:
+		return float64(v.Uint64()), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	case slog.KindDuration:
+		return v.Duration().Seconds(), true
+	}
+	return 0, false
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	var buf []byte
+	for _, c := range m.counters {
+		buf = fmt.Appendf(buf, "# TYPE %s counter\n%s %d\n", c.name, c.name, c.value)
+	}
+	for _, h := range m.histograms {
+		buf = fmt.Appendf(buf, "# TYPE %s histogram\n", h.name)
+		for i, le := range h.buckets {
+			buf = fmt.Appendf(buf, "%s_bucket{le=%q} %d\n", h.name, formatFloat(le), h.counts[i])
+		}
+		buf = fmt.Appendf(buf, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+		buf = fmt.Appendf(buf, "%s_sum %s\n%s_count %d\n", h.name, formatFloat(h.sum), h.name, h.count)
+	}
+	m.mu.Unlock()
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
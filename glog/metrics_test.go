@@ -0,0 +1,133 @@
+package glog
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMetricsNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     func(m *Metrics)
+		wantRules int
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			wantRules: 2,
+			rules: func(m *Metrics) {
+				m.CountWhere("payment_failed_total", nil)
+				m.HistogramWhere("http:request_duration_seconds", nil, "duration", nil)
+			},
+		},
+		{
+			name:      "invalid counter",
+			wantRules: 0,
+			rules:     func(m *Metrics) { m.CountWhere("payment-failed", nil) },
+			wantErr:   "invalid metric name",
+		},
+		{
+			name:      "leading digit",
+			wantRules: 0,
+			rules:     func(m *Metrics) { m.HistogramWhere("1st_duration", nil, "duration", nil) },
+			wantErr:   "invalid metric name",
+		},
+		{
+			name:      "empty",
+			wantRules: 0,
+			rules:     func(m *Metrics) { m.CountWhere("", nil) },
+			wantErr:   "invalid metric name",
+		},
+		{
+			name:      "duplicate counter",
+			wantRules: 1,
+			rules: func(m *Metrics) {
+				m.CountWhere("errors_total", nil)
+				m.CountWhere("errors_total", nil)
+			},
+			wantErr: "duplicate metric name",
+		},
+		{
+			name:      "counter and histogram",
+			wantRules: 1,
+			rules: func(m *Metrics) {
+				m.CountWhere("latency", nil)
+				m.HistogramWhere("latency", nil, "duration", nil)
+			},
+			wantErr: "duplicate metric name",
+		},
+		{
+			name:      "histogram series",
+			wantRules: 1,
+			rules: func(m *Metrics) {
+				m.HistogramWhere("latency", nil, "duration", nil)
+				m.CountWhere("latency_count", nil)
+			},
+			wantErr: "duplicate metric name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMetrics()
+			tt.rules(m)
+
+			_, err := NewLoggerE(WithOutput(io.Discard), WithMetrics(m))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("NewLoggerE() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("NewLoggerE() error = %v, want %q", err, tt.wantErr)
+			}
+
+			var out strings.Builder
+			m.WriteTo(&out)
+			if got := strings.Count(out.String(), "# TYPE"); got != tt.wantRules {
+				t.Fatalf("exposed %d rules, want %d:\n%s", got, tt.wantRules, out.String())
+			}
+		})
+	}
+}
+
+func TestMetricsObserve(t *testing.T) {
+	m := NewMetrics().
+		CountWhere("payment_failed_total", MessageIs("payment failed")).
+		HistogramWhere("charge_seconds", MessageIs("charged"), "duration", []float64{0.1, 1})
+	l := NewLogger(WithOutput(io.Discard), WithMetrics(m))
+
+	l.Error("payment failed")
+	l.Error("payment failed")
+	l.Info("charged", "duration", 0.5)
+
+	var out strings.Builder
+	m.WriteTo(&out)
+	for _, want := range []string{
+		"payment_failed_total 2\n",
+		`charge_seconds_bucket{le="0.1"} 0`,
+		`charge_seconds_bucket{le="1"} 1`,
+		"charge_seconds_count 1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestMetricsCountSampledRecords(t *testing.T) {
+	m := NewMetrics().CountWhere("request_total", MessageIs("request"))
+	l := NewLogger(WithOutput(io.Discard), WithMetrics(m),
+		WithSampling(map[slog.Level]float64{slog.LevelInfo: 0.1}, 0))
+
+	for i := 0; i < 100; i++ {
+		l.Info("request")
+		l.GetLogger("child").Info("request")
+	}
+
+	var out strings.Builder
+	m.WriteTo(&out)
+	if !strings.Contains(out.String(), "request_total 200\n") {
+		t.Fatalf("metrics missed sampled records:\n%s", out.String())
+	}
+}
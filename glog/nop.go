@@ -0,0 +1,30 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Nop returns a Logger that discards everything, for use as a library
+// default. Its Fatal method does not exit.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Trace(msg string, args ...any)            {}
+func (nopLogger) Debug(msg string, args ...any)            {}
+func (nopLogger) Info(msg string, args ...any)             {}
+func (nopLogger) Warn(msg string, args ...any)             {}
+func (nopLogger) Error(msg string, args ...any)            {}
+func (nopLogger) Fatal(msg string, args ...any)            {}
+func (l nopLogger) WithContext(ctx context.Context) Logger { return l }
+
+// discardHandler is disabled for every level
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
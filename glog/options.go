@@ -1,6 +1,15 @@
 package glog
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
 
 type Option func(*BaseLogger)
 
@@ -16,6 +25,123 @@ func WithName(name string) Option {
 	}
 }
 
+// WithOutput sets the writer records are written to, defaults to os.Stdout
+func WithOutput(w io.Writer) Option {
+	return func(bl *BaseLogger) {
+		bl.stdout = w
+		bl.stderr = nil
+	}
+}
+
+// WithOutputFile writes records to the file at path, see NewFileWriter.
+// The file is closed by the logger's Close method. If it cannot be opened
+// NewLoggerE returns the error and NewLogger keeps the previous output.
+func WithOutputFile(path string, opts ...FileOption) Option {
+	return func(bl *BaseLogger) {
+		w, err := NewFileWriter(path, opts...)
+		if err != nil {
+			bl.errs = append(bl.errs, fmt.Errorf("glog: open output %q: %w", path, err))
+			return
+		}
+		bl.stdout = w
+		bl.stderr = nil
+		bl.closers = append(bl.closers, w)
+	}
+}
+
+// WithLumberjack writes records to w, typically a *lumberjack.Logger,
+// which is rotated by the logger's Rotate method and closed by Close
+//
+//	glog.WithLumberjack(&lumberjack.Logger{Filename: "app.log", MaxSize: 100})
+func WithLumberjack(w RotatingWriter) Option {
+	return func(bl *BaseLogger) {
+		bl.stdout = w
+		bl.stderr = nil
+		bl.closers = append(bl.closers, w)
+	}
+}
+
+// WithBufferedOutput batches writes to the output, see
+// NewBufferedWriter. It wraps the output set so far, so it must follow
+// the output options. The logger's Flush and Close write the buffer.
+func WithBufferedOutput(size int, interval time.Duration) Option {
+	return func(bl *BaseLogger) {
+		w := NewBufferedWriter(bl.stdout, size, interval)
+		bl.stdout = w
+		// flush before the wrapped output is closed
+		bl.closers = append([]io.Closer{w}, bl.closers...)
+	}
+}
+
+// WithFlushTimeout bounds how long Flush, Close and Fatal wait for
+// handlers and sinks to write held records, DefaultFlushTimeout by
+// default
+func WithFlushTimeout(d time.Duration) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithFlushTimeout") {
+			return
+		}
+		bl.flushTimeout = d
+	}
+}
+
+// WithExitCodes maps the codes of errors logged by Fatal, those with a
+// Code() int method, to process exit codes. Errors without a code or
+// with an unmapped one exit with fallback.
+//
+//	// 78 for configuration errors, 69 for unavailable dependencies
+//	glog.WithExitCodes(map[int]int{1001: 78, 2001: 69}, 1)
+func WithExitCodes(codes map[int]int, fallback int) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithExitCodes") {
+			return
+		}
+		bl.exitCodes = maps.Clone(codes)
+		if bl.exitCodes == nil {
+			bl.exitCodes = map[int]int{}
+		}
+		bl.exitDefault = fallback
+	}
+}
+
+// WithDevelopment makes DPanic panic like Panic, to catch bugs early
+// in development and tests while production only logs them
+func WithDevelopment() Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithDevelopment") {
+			return
+		}
+		bl.development = true
+	}
+}
+
+// WithStderr writes records to os.Stderr
+func WithStderr() Option {
+	return WithOutput(os.Stderr)
+}
+
+// WithSplitStdStreams writes records below Warn to os.Stdout and the
+// others to os.Stderr, in the format of the logger type
+func WithSplitStdStreams() Option {
+	return func(bl *BaseLogger) {
+		bl.stdout = os.Stdout
+		bl.stderr = os.Stderr
+	}
+}
+
+// WithControlSocket serves control commands on the unix socket at path,
+// see ControlServer, once the logger is configured. The logger's Close
+// stops it. If it cannot listen NewLoggerE returns the error and
+// NewLogger logs without it.
+func WithControlSocket(path string) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithControlSocket") {
+			return
+		}
+		bl.controlPath = path
+	}
+}
+
 func WithContext(ctx context.Context) Option {
 	return func(bl *BaseLogger) {
 		bl.ctx = ctx
@@ -45,3 +171,498 @@ func WithLoggerTypeJSON() Option {
 		bl.loggerType = LoggerTypeJSON
 	}
 }
+
+// WithColorConsoleOptions sets the options used to build the
+// pretty handler when the logger type is LoggerTypePretty
+func WithColorConsoleOptions(opts ...ColorConsoleOption) Option {
+	return func(bl *BaseLogger) {
+		bl.consoleOpts = append(bl.consoleOpts, opts...)
+	}
+}
+
+// WithMaxMessageLength truncates log messages longer than n bytes
+func WithMaxMessageLength(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.maxMsgLen = n
+	}
+}
+
+// WithMaxValueLength truncates attr values longer than n bytes
+func WithMaxValueLength(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.maxValueLen = n
+	}
+}
+
+// WithMaxAttrs drops attrs beyond the first n of each record, the
+// number dropped is logged under RecordTruncatedKey
+func WithMaxAttrs(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.maxAttrs = n
+	}
+}
+
+// WithMaxRecordSize keeps the approximate encoded size of the message
+// and attrs of records under n bytes by dropping trailing attrs, e.g.
+// for UDP based sinks. Leave room for the time, level and source fields.
+func WithMaxRecordSize(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.maxSize = n
+	}
+}
+
+// WithFlattenGroups renders groups as dotted keys, e.g. http.method,
+// instead of nested JSON objects
+func WithFlattenGroups() Option {
+	return func(bl *BaseLogger) {
+		bl.flatten = true
+	}
+}
+
+// WithExpandDottedKeys renders dotted keys such as http.method as
+// nested JSON objects, attrs sharing a prefix are merged into one object
+func WithExpandDottedKeys() Option {
+	return func(bl *BaseLogger) {
+		bl.expand = true
+	}
+}
+
+// WithColor sets the color mode for the pretty handler
+func WithColor(mode ColorMode) Option {
+	return func(bl *BaseLogger) {
+		bl.consoleOpts = append(bl.consoleOpts, WithColorConsoleColorMode(mode))
+	}
+}
+
+// WithSourceFormat sets how the source attribute is rendered
+func WithSourceFormat(format SourceFormat) Option {
+	return func(bl *BaseLogger) {
+		bl.sourceFormat = format
+	}
+}
+
+// WithSourceTrimPrefix removes prefix, e.g. the module root,
+// from the source file path
+func WithSourceTrimPrefix(prefix string) Option {
+	return func(bl *BaseLogger) {
+		bl.sourceTrimPrefix = prefix
+	}
+}
+
+// WithSourceFunction includes the calling function in the source
+// attribute, rendered as "pkg.Func (file.go:123)"
+func WithSourceFunction(enabled bool) Option {
+	return func(bl *BaseLogger) {
+		bl.sourceFunc = enabled
+	}
+}
+
+// WithCallerSkip skips n additional stack frames when resolving the
+// call site, for packages that wrap the logger in their own helpers
+func WithCallerSkip(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.callerSkip = n
+	}
+}
+
+// WithBadKeyMode sets how malformed key value args, which slog logs
+// under "!BADKEY", are reported. Use BadKeyPanic in development to
+// catch them early.
+func WithBadKeyMode(mode BadKeyMode) Option {
+	return func(bl *BaseLogger) {
+		bl.badKeys = mode
+	}
+}
+
+// WithDedupKeys removes repeated attribute keys from records, e.g. a
+// request_id added both with With and at the call site, keeping the
+// value selected by mode
+func WithDedupKeys(mode DedupMode) Option {
+	return func(bl *BaseLogger) {
+		bl.dedup = true
+		bl.dedupMode = mode
+	}
+}
+
+// WithStackTraceLevel adds a stack trace to records at or above level,
+// Error and Fatal add one whenever an error is passed
+func WithStackTraceLevel(level slog.Level) Option {
+	return func(bl *BaseLogger) {
+		bl.stackTrace = true
+		bl.stackLevel = level
+	}
+}
+
+// WithStackTrace enables or disables stack traces, when disabled Error
+// and Fatal no longer capture one. Enabled by default.
+func WithStackTrace(enabled bool) Option {
+	return func(bl *BaseLogger) {
+		bl.stackOff = !enabled
+	}
+}
+
+// WithStackDepth captures at most n frames per stack trace, defaults to 32
+func WithStackDepth(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.stackDepth = n
+	}
+}
+
+// WithStackSkip drops the n innermost frames from stack traces, on top
+// of the frames skipped with WithCallerSkip
+func WithStackSkip(n int) Option {
+	return func(bl *BaseLogger) {
+		bl.stackSkip = n
+	}
+}
+
+// WithTimeFormat sets the layout used for the ts field in JSON and
+// console output, either a time layout or one of the TimeFormat
+// epoch constants
+func WithTimeFormat(layout string) Option {
+	return func(bl *BaseLogger) {
+		bl.timeFormat = layout
+	}
+}
+
+// WithUTC renders timestamps in UTC in all output formats
+func WithUTC() Option {
+	return WithTimezone(time.UTC)
+}
+
+// WithTimezone renders timestamps in loc in all output formats
+func WithTimezone(loc *time.Location) Option {
+	return func(bl *BaseLogger) {
+		bl.timeLocation = loc
+	}
+}
+
+// WithTimeKey renames the timestamp field, defaults to "ts"
+func WithTimeKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.time = key
+	}
+}
+
+// WithLevelKey renames the level field, defaults to "level"
+func WithLevelKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.level = key
+	}
+}
+
+// WithMessageKey renames the message field, defaults to "msg"
+func WithMessageKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.message = key
+	}
+}
+
+// WithLoggerKey renames the logger name field, defaults to "logger"
+func WithLoggerKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.logger = key
+	}
+}
+
+// WithErrorKey renames the error field, defaults to "error"
+func WithErrorKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.error = key
+	}
+}
+
+// WithStackKey renames the stack trace field, defaults to "stack"
+func WithStackKey(key string) Option {
+	return func(bl *BaseLogger) {
+		bl.keys.stack = key
+	}
+}
+
+// WithReplaceAttr adds fn to the ReplaceAttr chain. Functions run in the
+// order they are added, after the built in key and value rewriting.
+func WithReplaceAttr(fn func(groups []string, a slog.Attr) slog.Attr) Option {
+	return func(bl *BaseLogger) {
+		bl.replaceAttrs = append(bl.replaceAttrs, fn)
+	}
+}
+
+// WithRedactKeys replaces the values of attributes named like keys
+// with RedactedValue, e.g. "password" or "authorization"
+func WithRedactKeys(keys ...string) Option {
+	return WithReplaceAttr(redactAttr(keys))
+}
+
+// WithDefaultAttrs adds attributes to every record of the logger
+// and of all loggers created with GetLogger
+func WithDefaultAttrs(args ...any) Option {
+	return func(bl *BaseLogger) {
+		bl.defaultAttrs = append(slices.Clone(bl.defaultAttrs), argsToAttrs(args)...)
+	}
+}
+
+// WithHostInfo adds hostname, pid and go_version to every record
+func WithHostInfo() Option {
+	return func(bl *BaseLogger) {
+		bl.defaultAttrs = append(slices.Clone(bl.defaultAttrs), HostAttrs()...)
+	}
+}
+
+// WithBuildInfo adds module.version, vcs.revision and vcs.time to every record
+func WithBuildInfo() Option {
+	return func(bl *BaseLogger) {
+		bl.defaultAttrs = append(slices.Clone(bl.defaultAttrs), BuildAttrs()...)
+	}
+}
+
+// WithEnrichers adds enrichers evaluated for every record
+func WithEnrichers(enrichers ...Enricher) Option {
+	return func(bl *BaseLogger) {
+		bl.enrichers = append(slices.Clone(bl.enrichers), enrichers...)
+	}
+}
+
+// WithAttrProviders adds providers evaluated for every record, unlike
+// With their attributes reflect the state at log time
+func WithAttrProviders(providers ...AttrProvider) Option {
+	return func(bl *BaseLogger) {
+		enrichers := make([]Enricher, 0, len(providers))
+		for _, provider := range providers {
+			enrichers = append(enrichers, ProviderEnricher(provider))
+		}
+		bl.enrichers = append(slices.Clone(bl.enrichers), enrichers...)
+	}
+}
+
+// WithGoroutineID adds the goroutine ID to every record.
+// This is costly and intended for development builds only.
+func WithGoroutineID() Option {
+	return WithEnrichers(GoroutineEnricher())
+}
+
+// WithRuntimeStats adds a "runtime" group with goroutine, heap and GC
+// stats to records at or above level
+func WithRuntimeStats(level slog.Level) Option {
+	return func(bl *BaseLogger) {
+		bl.runtimeStats = true
+		bl.runtimeStatsLevel = level
+	}
+}
+
+// WithSequence adds a per logger "seq" attribute that increases
+// by one with every record
+func WithSequence() Option {
+	return func(bl *BaseLogger) {
+		bl.sequence = true
+	}
+}
+
+// WithUptime adds an "uptime" attribute with the time elapsed since
+// the root logger was created
+func WithUptime() Option {
+	return func(bl *BaseLogger) {
+		bl.uptime = true
+	}
+}
+
+// WithAuditOutput routes audit records to w instead of the logger output
+func WithAuditOutput(w io.Writer) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithAuditOutput") {
+			return
+		}
+		bl.auditOut = w
+	}
+}
+
+// WithAuditChain hash chains audit records so that each one includes
+// the SHA-256 of the previous record. If anchorEvery is positive an
+// anchor record with the chain head is written every anchorEvery records.
+// Each process starts with a segment record. When the audit output is
+// a file, see FileWriter, the chain continues from its last record.
+// Use VerifyAuditChain to check an audit file.
+func WithAuditChain(anchorEvery int) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithAuditChain") {
+			return
+		}
+		bl.auditChain = true
+		bl.auditAnchorEvery = anchorEvery
+	}
+}
+
+// WithAuditChainKey signs the anchor and segment records of the audit
+// chain with HMAC-SHA256 and key, so that whoever can edit the audit
+// file cannot rewrite the chain without the key. It implies
+// WithAuditChain(0) unless set. Use VerifyAuditChainKey to check it.
+func WithAuditChainKey(key []byte) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithAuditChainKey") {
+			return
+		}
+		bl.auditChain = true
+		bl.auditChainKey = slices.Clone(key)
+	}
+}
+
+// WithRequiredAttrs requires records at or above level to include keys.
+// Violations panic with RequiredAttrsPanic or emit a warning record
+// with RequiredAttrsWarn.
+func WithRequiredAttrs(level slog.Level, mode RequiredAttrsMode, keys ...string) Option {
+	return func(bl *BaseLogger) {
+		bl.requiredAttrs = keys
+		bl.requiredAttrsLevel = level
+		bl.requiredAttrsMode = mode
+	}
+}
+
+// WithHMAC signs every record with HMAC-SHA256 using key, see
+// VerifyHMACLine to check signatures on the collector side
+func WithHMAC(key []byte) Option {
+	return func(bl *BaseLogger) {
+		bl.hmacKey = key
+	}
+}
+
+// WithSampledSpanLevel lowers the level to level for records whose
+// context carries a sampled span, so the traced share of requests has
+// rich logs while the rest stays quiet. Spans are found like FromSpan
+// does, see WithSpanExtractor.
+func WithSampledSpanLevel(level slog.Level) Option {
+	return func(bl *BaseLogger) {
+		bl.sampledLevel = level
+		bl.sampledLevelOn = true
+	}
+}
+
+// WithSpanExtractor sets how FromSpan finds the active span of a
+// context, defaults to SpanFromContext
+func WithSpanExtractor(fn SpanExtractor) Option {
+	return func(bl *BaseLogger) {
+		bl.spanExtractor = fn
+	}
+}
+
+// WithStream publishes records to stream so clients connected to its
+// HTTP endpoint can tail the logger output
+func WithStream(stream *LogStream) Option {
+	return func(bl *BaseLogger) {
+		bl.stream = stream
+	}
+}
+
+// WithMetrics applies the rules of m to the records of every logger
+// in the tree that pass the level and focus checks, before sampling
+// and the error breaker drop any, so counts are exact. Rules see the
+// attributes of the record and of With, not those added by enrichers.
+func WithMetrics(m *Metrics) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithMetrics") {
+			return
+		}
+		if err := m.Err(); err != nil {
+			bl.errs = append(bl.errs, err)
+		}
+		bl.metricSubs.observe(nil, m.observe)
+	}
+}
+
+// WithRecentBuffer keeps the last size records of each logger in
+// memory for retrieval with Recent, e.g. to attach to crash reports
+func WithRecentBuffer(size int) Option {
+	return func(bl *BaseLogger) {
+		bl.recentSize = size
+	}
+}
+
+// WithCrashDir writes a crash bundle to dir on Fatal and on panics
+// caught with CatchPanic, with the record, its stack, the records kept
+// by WithRecentBuffer and build info, so postmortems do not depend on
+// remote sinks having received the last lines
+func WithCrashDir(dir string) Option {
+	return func(bl *BaseLogger) {
+		bl.crashDir = dir
+	}
+}
+
+// WithDiscard drops every record before any formatting is done,
+// Fatal still exits
+func WithDiscard() Option {
+	return func(bl *BaseLogger) {
+		bl.discard = true
+	}
+}
+
+// WithTenantSink sends the records of the tenant logger for id to w,
+// see BaseLogger.ForTenant. Passed to GetLogger it applies to the
+// tenants of that child only, as does WithTenantLevel.
+func WithTenantSink(id string, w io.Writer) Option {
+	return func(bl *BaseLogger) {
+		bl.tenantConfig().sinks[id] = w
+	}
+}
+
+// WithTenantLevel sets the level of the tenant logger for id,
+// see BaseLogger.ForTenant
+func WithTenantLevel(id string, level string) Option {
+	return func(bl *BaseLogger) {
+		bl.tenantConfig().levels[id] = level
+	}
+}
+
+// WithSampling keeps the given fraction of records per level, e.g.
+// {slog.LevelInfo: 0.1, slog.LevelDebug: 0.01}. Sampling applies after
+// focus filtering. If interval is positive a summary with the number of
+// records sampled out is logged at most once per interval.
+func WithSampling(rates map[slog.Level]float64, interval time.Duration) Option {
+	return func(bl *BaseLogger) {
+		bl.sampleRates = rates
+		bl.sampleInterval = interval
+		bl.sampling = nil
+	}
+}
+
+// WithErrorBreaker opens a circuit on a logger that logs more than
+// threshold errors within window, records below Error are then dropped
+// for cooldown. Each logger of the tree has its own circuit.
+func WithErrorBreaker(threshold int, window, cooldown time.Duration) Option {
+	return func(bl *BaseLogger) {
+		switch {
+		case threshold <= 0:
+			bl.errs = append(bl.errs, fmt.Errorf("glog: error breaker threshold must be positive, got %d", threshold))
+			return
+		case window <= 0:
+			bl.errs = append(bl.errs, fmt.Errorf("glog: error breaker window must be positive, got %v", window))
+			return
+		case cooldown <= 0:
+			bl.errs = append(bl.errs, fmt.Errorf("glog: error breaker cooldown must be positive, got %v", cooldown))
+			return
+		}
+		bl.breakerThreshold = threshold
+		bl.breakerWindow = window
+		bl.breakerCooldown = cooldown
+	}
+}
+
+// WithAsync writes records from a background goroutine through a queue
+// of size records, see AsyncOption for the backpressure settings.
+// Call Close before exiting to drain the queue.
+func WithAsync(size int, opts ...AsyncOption) Option {
+	return func(bl *BaseLogger) {
+		bl.asyncSize = size
+		bl.asyncOpts = opts
+	}
+}
+
+// WithDropReport logs a "records dropped" warning with the number of
+// records dropped per reason at most once per interval, so that loss
+// from sampling, full queues or failing outputs is visible. With
+// sampling enabled it replaces the sampling summary.
+func WithDropReport(interval time.Duration) Option {
+	return func(bl *BaseLogger) {
+		if !bl.treeOption("WithDropReport") {
+			return
+		}
+		bl.drops.interval = interval
+	}
+}
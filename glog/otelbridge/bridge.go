@@ -0,0 +1,152 @@
+// Package otelbridge implements the OpenTelemetry Logs Bridge API on top
+// of glog, so records emitted by libraries instrumented with the OTel
+// Logs API are rendered by glog handlers even without a collector.
+//
+//	global.SetLoggerProvider(otelbridge.NewLoggerProvider(logger))
+package otelbridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goliatone/go-logger/glog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+// EventNameKey holds the event name of OTel records that have one
+const EventNameKey = "event"
+
+// LoggerProvider hands out OTel loggers backed by a glog logger
+type LoggerProvider struct {
+	embedded.LoggerProvider
+	logger *glog.BaseLogger
+}
+
+// NewLoggerProvider returns a provider whose loggers write through
+// logger. Named loggers map to logger.GetLogger(name).
+func NewLoggerProvider(logger *glog.BaseLogger) *LoggerProvider {
+	return &LoggerProvider{logger: logger}
+}
+
+// Logger implements log.LoggerProvider
+func (p *LoggerProvider) Logger(name string, options ...log.LoggerOption) log.Logger {
+	logger := p.logger
+	if name != "" {
+		logger = logger.GetLogger(name)
+	}
+
+	cfg := log.NewLoggerConfig(options...)
+	if version := cfg.InstrumentationVersion(); version != "" {
+		logger = logger.With(slog.String("scope_version", version))
+	}
+
+	return &Logger{logger: logger}
+}
+
+// Logger emits OTel records to a glog logger
+type Logger struct {
+	embedded.Logger
+	logger *glog.BaseLogger
+}
+
+// Enabled implements log.Logger
+func (l *Logger) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return l.logger.Enabled(Level(param.Severity))
+}
+
+// Emit implements log.Logger
+func (l *Logger) Emit(ctx context.Context, record log.Record) {
+	ts := record.Timestamp()
+	if ts.IsZero() {
+		ts = record.ObservedTimestamp()
+	}
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	body := record.Body()
+	var msg string
+	if body.Kind() == log.KindString {
+		msg = body.AsString()
+	}
+
+	r := slog.NewRecord(ts, Level(record.Severity()), msg, 0)
+
+	if body.Kind() != log.KindString && body.Kind() != log.KindEmpty {
+		r.AddAttrs(slog.Attr{Key: "body", Value: convertValue(body)})
+	}
+	if name := record.EventName(); name != "" {
+		r.AddAttrs(slog.String(EventNameKey, name))
+	}
+
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		r.AddAttrs(slog.Attr{Key: kv.Key, Value: convertValue(kv.Value)})
+		return true
+	})
+
+	l.logger.LogRecord(ctx, r)
+}
+
+// Level maps an OTel severity to the glog level, e.g. SeverityInfo to
+// slog.LevelInfo and SeverityFatal to glog.LevelFatal. Undefined
+// severities map to slog.LevelInfo.
+func Level(sev log.Severity) slog.Level {
+	switch {
+	case sev == log.SeverityUndefined:
+		return slog.LevelInfo
+	case sev >= log.SeverityFatal:
+		return glog.LevelFatal + slog.Level(sev-log.SeverityFatal)
+	default:
+		return slog.Level(sev - log.SeverityInfo)
+	}
+}
+
+func convertValue(v log.Value) slog.Value {
+	switch v.Kind() {
+	case log.KindBool:
+		return slog.BoolValue(v.AsBool())
+	case log.KindFloat64:
+		return slog.Float64Value(v.AsFloat64())
+	case log.KindInt64:
+		return slog.Int64Value(v.AsInt64())
+	case log.KindString:
+		return slog.StringValue(v.AsString())
+	case log.KindBytes:
+		return slog.AnyValue(v.AsBytes())
+	case log.KindSlice:
+		items := v.AsSlice()
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			out = append(out, plainValue(convertValue(item)))
+		}
+		return slog.AnyValue(out)
+	case log.KindMap:
+		kvs := v.AsMap()
+		attrs := make([]slog.Attr, 0, len(kvs))
+		for _, kv := range kvs {
+			attrs = append(attrs, slog.Attr{Key: kv.Key, Value: convertValue(kv.Value)})
+		}
+		return slog.GroupValue(attrs...)
+	case log.KindEmpty:
+		return slog.AnyValue(nil)
+	default:
+		return slog.StringValue(fmt.Sprint(v))
+	}
+}
+
+// plainValue returns v as a Go value, groups become maps as slices of
+// attrs would render as Key and Value pairs inside slices
+func plainValue(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	attrs := v.Group()
+	out := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		out[a.Key] = plainValue(a.Value)
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package otelbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/go-logger/glog"
+	"go.opentelemetry.io/otel/log"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		sev  log.Severity
+		want slog.Level
+	}{
+		{log.SeverityUndefined, slog.LevelInfo},
+		{log.SeverityTrace, glog.LevelTrace},
+		{log.SeverityDebug, slog.LevelDebug},
+		{log.SeverityInfo, slog.LevelInfo},
+		{log.SeverityInfo2, slog.LevelInfo + 1},
+		{log.SeverityWarn, slog.LevelWarn},
+		{log.SeverityError, slog.LevelError},
+		{log.SeverityError4, slog.LevelError + 3},
+		{log.SeverityFatal, glog.LevelFatal},
+		{log.SeverityFatal4, glog.LevelFatal + 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sev.String(), func(t *testing.T) {
+			if got := Level(tt.sev); got != tt.want {
+				t.Fatalf("Level(%v) = %v, want %v", tt.sev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmit(t *testing.T) {
+	record := func(body log.Value, event string, attrs ...log.KeyValue) log.Record {
+		var r log.Record
+		r.SetSeverity(log.SeverityWarn)
+		r.SetBody(body)
+		r.SetEventName(event)
+		r.AddAttributes(attrs...)
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		record log.Record
+		want   map[string]any
+	}{
+		{
+			name:   "string body",
+			record: record(log.StringValue("cache miss"), ""),
+			want:   map[string]any{"msg": "cache miss", "level": "warn"},
+		},
+		{
+			name:   "empty body",
+			record: record(log.Value{}, ""),
+			want:   map[string]any{"msg": ""},
+		},
+		{
+			name:   "structured body",
+			record: record(log.MapValue(log.String("op", "get")), ""),
+			want:   map[string]any{"msg": "", "body": map[string]any{"op": "get"}},
+		},
+		{
+			name:   "event name",
+			record: record(log.StringValue("login"), "user.login"),
+			want:   map[string]any{"msg": "login", EventNameKey: "user.login"},
+		},
+		{
+			name: "attributes",
+			record: record(log.StringValue("request"), "",
+				log.Bool("ok", true),
+				log.Int64("status", 200),
+				log.Float64("ratio", 0.5),
+				log.Slice("ids", log.Int64Value(1), log.StringValue("two")),
+				log.Map("user", log.String("name", "bob"), log.Map("org", log.String("id", "acme"))),
+			),
+			want: map[string]any{
+				"ok":     true,
+				"status": float64(200),
+				"ratio":  0.5,
+				"ids":    []any{float64(1), "two"},
+				"user":   map[string]any{"name": "bob", "org": map[string]any{"id": "acme"}},
+			},
+		},
+		{
+			name: "maps in slices",
+			record: record(log.StringValue("batch"), "",
+				log.Slice("items", log.MapValue(log.String("sku", "a1")), log.SliceValue(log.BoolValue(true))),
+			),
+			want: map[string]any{
+				"items": []any{map[string]any{"sku": "a1"}, []any{true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			provider := NewLoggerProvider(glog.NewLogger(glog.WithOutput(&out)))
+
+			provider.Logger("").Emit(context.Background(), tt.record)
+
+			var got map[string]any
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("invalid output %q: %v", out.String(), err)
+			}
+			for k, want := range tt.want {
+				if !reflect.DeepEqual(got[k], want) {
+					t.Errorf("%s = %#v, want %#v", k, got[k], want)
+				}
+			}
+			if _, ok := tt.want["body"]; !ok {
+				if body, ok := got["body"]; ok {
+					t.Errorf("unexpected body %v", body)
+				}
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	logger := glog.NewLogger(glog.WithOutput(&bytes.Buffer{}), glog.WithLevel(glog.Warn))
+	l := NewLoggerProvider(logger).Logger("db")
+
+	if l.Enabled(context.Background(), log.EnabledParameters{Severity: log.SeverityInfo}) {
+		t.Error("Info enabled on a Warn logger")
+	}
+	if !l.Enabled(context.Background(), log.EnabledParameters{Severity: log.SeverityError}) {
+		t.Error("Error disabled on a Warn logger")
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/goliatone/go-logger/glog/otelbridge
+
+go 1.23.4
+
+require (
+	github.com/goliatone/go-logger v0.1.1
+	go.opentelemetry.io/otel/log v0.14.0
+)
+
+require (
+	github.com/fatih/color v1.18.0 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel v1.38.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+)
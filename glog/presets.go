@@ -0,0 +1,42 @@
+package glog
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ProductionSampling is the sampling used by NewProduction, errors and
+// warnings are always kept
+var ProductionSampling = map[slog.Level]float64{
+	slog.LevelDebug: 0.01,
+	slog.LevelInfo:  0.1,
+}
+
+// NewDevelopment returns a logger with pretty output at trace level,
+// source info, stack traces on warnings and above, warnings for
+// malformed args and DPanic panicking. options are applied after the
+// preset.
+func NewDevelopment(options ...Option) *BaseLogger {
+	preset := []Option{
+		WithLoggerTypePretty(),
+		WithLevel(Trace),
+		WithStackTraceLevel(slog.LevelWarn),
+		WithBadKeyMode(BadKeyWarn),
+		WithDevelopment(),
+	}
+	return NewLogger(append(preset, options...)...)
+}
+
+// NewProduction returns a logger with JSON output at info level,
+// ProductionSampling, package relative source paths and a drop report
+// every minute. options are applied after the preset.
+func NewProduction(options ...Option) *BaseLogger {
+	preset := []Option{
+		WithLoggerTypeJSON(),
+		WithLevel(Info),
+		WithSourceFormat(SourcePackage),
+		WithSampling(ProductionSampling, 0),
+		WithDropReport(time.Minute),
+	}
+	return NewLogger(append(preset, options...)...)
+}
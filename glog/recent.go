@@ -0,0 +1,81 @@
+package glog
+
+import (
+	"slices"
+	"sync"
+)
+
+// recentBuffer is a fixed size ring of the last records of a logger
+type recentBuffer struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+func newRecentBuffer(size int) *recentBuffer {
+	return &recentBuffer{records: make([]Record, size)}
+}
+
+func (b *recentBuffer) add(rec Record) {
+	b.mu.Lock()
+	b.records[b.next] = rec
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.mu.Unlock()
+}
+
+// snapshot returns the buffered records oldest first
+func (b *recentBuffer) snapshot() []Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return slices.Clone(b.records[:b.next])
+	}
+	return append(slices.Clone(b.records[b.next:]), b.records[:b.next]...)
+}
+
+// Recent returns up to n of the latest buffered records matching all
+// filters, oldest first. On the root logger records from every logger
+// in the tree are merged. Buffering is enabled with WithRecentBuffer,
+// otherwise Recent returns nil.
+func (c *BaseLogger) Recent(n int, filters ...RecordFilter) []Record {
+	var records []Record
+
+	if c.getRoot() == c {
+		c.mu.RLock()
+		loggers := make([]*BaseLogger, 0, len(c.loggers))
+		for _, logger := range c.loggers {
+			loggers = append(loggers, logger)
+		}
+		c.mu.RUnlock()
+
+		for _, logger := range append(loggers, c) {
+			if logger.recent != nil {
+				records = append(records, logger.recent.snapshot()...)
+			}
+		}
+		slices.SortStableFunc(records, func(a, b Record) int {
+			return a.Time.Compare(b.Time)
+		})
+	} else if c.recent != nil {
+		records = c.recent.snapshot()
+	}
+
+	records = slices.DeleteFunc(records, func(r Record) bool {
+		for _, filter := range filters {
+			if !filter(r) {
+				return true
+			}
+		}
+		return false
+	})
+
+	if n >= 0 && len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records
+}
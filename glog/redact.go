@@ -0,0 +1,25 @@
+package glog
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted attributes
+const RedactedValue = "[REDACTED]"
+
+// redactAttr returns a ReplaceAttr function that hides the values of
+// keys, matched case insensitively at any group depth
+func redactAttr(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = true
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if set[strings.ToLower(a.Key)] {
+			return slog.String(a.Key, RedactedValue)
+		}
+		return a
+	}
+}
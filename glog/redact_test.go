@@ -0,0 +1,87 @@
+package glog
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRedactKeys(t *testing.T) {
+	types := []string{LoggerTypeJSON, LoggerTypeConsole, LoggerTypePretty}
+
+	tests := []struct {
+		name string
+		log  func(l *BaseLogger)
+	}{
+		{
+			name: "record attrs",
+			log: func(l *BaseLogger) {
+				l.Info("x", "password", "secret1")
+			},
+		},
+		{
+			name: "with",
+			log: func(l *BaseLogger) {
+				l.With("password", "secret1").Info("x")
+			},
+		},
+		{
+			name: "with group",
+			log: func(l *BaseLogger) {
+				l.With(slog.Group("user", "password", "secret1")).Info("x")
+			},
+		},
+		{
+			name: "child logger",
+			log: func(l *BaseLogger) {
+				l.GetLogger("db").With("Password", "secret1").Info("x")
+			},
+		},
+	}
+
+	for _, loggerType := range types {
+		for _, tt := range tests {
+			t.Run(loggerType+"/"+tt.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				l := NewLogger(
+					WithOutput(&buf),
+					WithLoggerType(loggerType),
+					WithRedactKeys("password"),
+				)
+
+				tt.log(l)
+
+				out := buf.String()
+				if strings.Contains(out, "secret1") {
+					t.Fatalf("secret leaked: %s", out)
+				}
+				if !strings.Contains(out, RedactedValue) {
+					t.Fatalf("missing %s: %s", RedactedValue, out)
+				}
+			})
+		}
+	}
+}
+
+func TestColorConsoleWithAttrsGroups(t *testing.T) {
+	var buf bytes.Buffer
+	var seen []string
+
+	h := NewColorConsoleHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			seen = append(seen, strings.Join(append(groups, a.Key), "."))
+			return a
+		},
+	})
+
+	slog.New(h).WithGroup("req").With("id", 1).Info("x")
+
+	if !slices.Contains(seen, "req.id") {
+		t.Fatalf("ReplaceAttr saw %v, want req.id", seen)
+	}
+	if !strings.Contains(buf.String(), "req.id") {
+		t.Fatalf("missing grouped key: %s", buf.String())
+	}
+}
@@ -0,0 +1,224 @@
+package glog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RemoteConfig is the runtime configuration a ConfigSource provides.
+// Empty fields leave the current configuration unchanged.
+//
+//	{"level": "info", "levels": {"db": "debug"}, "focus": [], "sampling": {"debug": 0.1}}
+type RemoteConfig struct {
+	// Level is the level of the root logger
+	Level string `json:"level" yaml:"level" mapstructure:"level"`
+	// Levels maps child logger names to their level
+	Levels map[string]string `json:"levels" yaml:"levels" mapstructure:"levels"`
+	// Focus restricts output to the named loggers, an empty non nil
+	// list restores output for all loggers
+	Focus []string `json:"focus" yaml:"focus" mapstructure:"focus"`
+	// Sampling maps level names to the fraction of records kept
+	Sampling map[string]float64 `json:"sampling" yaml:"sampling" mapstructure:"sampling"`
+}
+
+// validate checks every value so a config is applied entirely or not
+// at all
+func (rc RemoteConfig) validate() error {
+	var errs []error
+	if rc.Level != "" {
+		if _, err := ParseLevel(rc.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, level := range rc.Levels {
+		if _, err := ParseLevel(level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := parseSampling(rc.Sampling); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ConfigSource fetches the remote configuration, e.g. from an HTTP
+// endpoint, an object store or a key value store
+type ConfigSource interface {
+	Fetch(ctx context.Context) (RemoteConfig, error)
+}
+
+// ConfigSourceFunc adapts a function to ConfigSource, e.g. to read an
+// etcd key with the etcd client
+type ConfigSourceFunc func(ctx context.Context) (RemoteConfig, error)
+
+func (f ConfigSourceFunc) Fetch(ctx context.Context) (RemoteConfig, error) {
+	return f(ctx)
+}
+
+// maxRemoteConfigSize bounds the body read by HTTPConfigSource
+const maxRemoteConfigSize = 1 << 20
+
+// HTTPConfigSource fetches a JSON RemoteConfig from url with client, or
+// http.DefaultClient when nil. It works for any store that serves
+// objects over HTTP, such as presigned S3 URLs or Consul KV with ?raw.
+func HTTPConfigSource(url string, client *http.Client) ConfigSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return ConfigSourceFunc(func(ctx context.Context) (RemoteConfig, error) {
+		var rc RemoteConfig
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return rc, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return rc, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return rc, fmt.Errorf("glog: remote config: %s", resp.Status)
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize))
+		if err != nil {
+			return rc, err
+		}
+		if err := json.Unmarshal(body, &rc); err != nil {
+			return rc, fmt.Errorf("glog: remote config: %w", err)
+		}
+		return rc, nil
+	})
+}
+
+// MinPollInterval is the shortest interval PollConfig polls at
+const MinPollInterval = time.Second
+
+// PollConfig fetches the configuration from src about every interval,
+// with up to 10% jitter so a fleet does not poll in lockstep, and
+// applies it to the logger tree until stop is called. Configurations
+// that fail to fetch or validate are skipped and the last good one
+// stays in effect, a warning is logged when polling starts failing.
+// Intervals below MinPollInterval are raised to it.
+//
+//	stop := logger.PollConfig(glog.HTTPConfigSource(url, nil), time.Minute)
+//	defer stop()
+func (c *BaseLogger) PollConfig(src ConfigSource, interval time.Duration) (stop func()) {
+	root := c.getRoot()
+	if interval < MinPollInterval {
+		root.Warn("glog: poll interval raised", "interval", interval, "min", MinPollInterval)
+		interval = MinPollInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		var last *RemoteConfig
+		failing := false
+
+		for {
+			rc, err := src.Fetch(ctx)
+			if err == nil {
+				err = rc.validate()
+			}
+
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if !failing {
+					root.Warn("remote config rejected, keeping the last good config", Err(err))
+				}
+				failing = true
+			default:
+				failing = false
+				if last == nil || !reflect.DeepEqual(*last, rc) {
+					root.applyRemoteConfig(rc)
+					last = &rc
+				}
+			}
+
+			timer := time.NewTimer(jitter(interval))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// applyRemoteConfig applies a validated config to the tree of c
+func (c *BaseLogger) applyRemoteConfig(rc RemoteConfig) {
+	if rc.Level != "" {
+		c.WithLevel(rc.Level)
+	}
+	for name, level := range rc.Levels {
+		if logger, ok := c.Lookup(name); ok {
+			logger.WithLevel(level)
+		}
+	}
+
+	if rc.Focus != nil {
+		if len(rc.Focus) == 0 {
+			c.Unfocus()
+		} else {
+			c.Focus(rc.Focus...)
+		}
+	}
+
+	if rc.Sampling != nil {
+		rates, _ := parseSampling(rc.Sampling)
+		c.SetSampling(rates)
+	}
+}
+
+// SetSampling replaces the sampling rates of every logger in the tree
+// at runtime, including their copies and tenant loggers, see
+// WithSampling. Empty rates disable sampling. Counters of levels that
+// keep a rate continue, so repeated updates do not reset them.
+func (c *BaseLogger) SetSampling(rates map[slog.Level]float64) {
+	root := c.getRoot()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	// children created later start with these rates
+	root.sampleRates = rates
+	root.sampling.set(rates)
+	for _, logger := range root.loggers {
+		logger.sampleRates = rates
+		logger.sampling.set(rates)
+	}
+}
+
+// jitter returns d changed by a random amount of up to 10%
+func jitter(d time.Duration) time.Duration {
+	spread := int64(d) / 10
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(rand.Int64N(2*spread))
+}
@@ -0,0 +1,27 @@
+package glog
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollConfigMinInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second, time.Millisecond} {
+		var fetches atomic.Int32
+		src := ConfigSourceFunc(func(context.Context) (RemoteConfig, error) {
+			fetches.Add(1)
+			return RemoteConfig{}, nil
+		})
+
+		stop := NewLogger(WithOutput(io.Discard)).PollConfig(src, interval)
+		time.Sleep(50 * time.Millisecond)
+		stop()
+
+		if n := fetches.Load(); n != 1 {
+			t.Fatalf("interval %v: fetched %d times in 50ms, want 1", interval, n)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// RuntimeKey groups the runtime stats attributes
+const RuntimeKey = "runtime"
+
+// runtimeStatsMaxAge bounds how often runtime stats are read, records
+// logged in a burst share one sample
+const runtimeStatsMaxAge = time.Second
+
+type runtimeStatsSample struct {
+	at    time.Time
+	attrs []slog.Attr
+}
+
+var runtimeStats struct {
+	mu     sync.Mutex
+	sample runtimeStatsSample
+}
+
+// RuntimeStats returns goroutines, heap_alloc, gc_count, gc_pause_last
+// and gc_pause_total. Values are sampled at most once per second and
+// read without stopping the world.
+func RuntimeStats() []slog.Attr {
+	runtimeStats.mu.Lock()
+	defer runtimeStats.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(runtimeStats.sample.at) < runtimeStatsMaxAge {
+		return runtimeStats.sample.attrs
+	}
+
+	heap := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(heap)
+
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+
+	var lastPause time.Duration
+	if len(gc.Pause) > 0 {
+		lastPause = gc.Pause[0]
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("goroutines", runtime.NumGoroutine()),
+	}
+	if heap[0].Value.Kind() == metrics.KindUint64 {
+		attrs = append(attrs, slog.Uint64("heap_alloc", heap[0].Value.Uint64()))
+	}
+	attrs = append(attrs,
+		slog.Int64("gc_count", gc.NumGC),
+		slog.Duration("gc_pause_last", lastPause),
+		slog.Duration("gc_pause_total", gc.PauseTotal),
+	)
+
+	runtimeStats.sample = runtimeStatsSample{at: now, attrs: attrs}
+	return attrs
+}
+
+// RuntimeStatsEnricher adds the RuntimeStats group to records at or
+// above level, e.g. to correlate errors with resource pressure
+func RuntimeStatsEnricher(level slog.Level) Enricher {
+	return func(ctx context.Context, r slog.Record) []slog.Attr {
+		if r.Level < level {
+			return nil
+		}
+		return []slog.Attr{slog.Any(RuntimeKey, slog.GroupValue(RuntimeStats()...))}
+	}
+}
@@ -0,0 +1,47 @@
+package glog
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// needsSanitize reports whether s contains line breaks, other control
+// characters or invalid UTF-8
+func needsSanitize[T string | []byte](s T) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < ' ' && c != '\t' || c == 0x7f || c >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// appendSanitized appends s with line breaks and control characters
+// escaped and invalid UTF-8 replaced, so that a value can not start
+// what looks like a new log line
+func appendSanitized(buf []byte, s string) []byte {
+	if !needsSanitize(s) {
+		return append(buf, s...)
+	}
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, "\uFFFD"...)
+		case r == '\n':
+			buf = append(buf, `\n`...)
+		case r == '\r':
+			buf = append(buf, `\r`...)
+		case r == '\t' || unicode.IsPrint(r):
+			buf = append(buf, s[i:i+size]...)
+		default:
+			quoted := strconv.QuoteRuneToASCII(r)
+			buf = append(buf, quoted[1:len(quoted)-1]...)
+		}
+		i += size
+	}
+	return buf
+}
@@ -0,0 +1,49 @@
+package glog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAppendSanitized(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "user logged in", "user logged in"},
+		{"tab", "a\tb", "a\tb"},
+		{"newline", "a\nb", `a\nb`},
+		{"carriage return", "a\r\nb", `a\r\nb`},
+		{"escape", "a\x1b[31mb", `a\x1b[31mb`},
+		{"nul", "a\x00b", `a\x00b`},
+		{"delete", "a\x7fb", `a\x7fb`},
+		{"unicode", "café ✓", "café ✓"},
+		{"invalid utf8", "a\xffb", "a�b"},
+		{"line separator", "a\u2028b", `a\u2028b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(appendSanitized(nil, tt.in)); got != tt.want {
+				t.Fatalf("appendSanitized(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleLogInjection(t *testing.T) {
+	for _, loggerType := range []string{LoggerTypeConsole, LoggerTypePretty} {
+		t.Run(loggerType, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(WithOutput(&buf), WithLoggerType(loggerType))
+
+			l.Info("login failed\nINFO forged record", "user", "eve\r\nINFO forged attr")
+
+			if lines := strings.Count(strings.TrimSuffix(buf.String(), "\n"), "\n"); lines != 0 {
+				t.Fatalf("one record was written as %d lines:\n%s", lines+1, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package glog
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed schema/record.schema.json
+var recordSchema []byte
+
+//go:embed schema/audit.schema.json
+var auditSchema []byte
+
+// ErrSchemaViolation is returned when a record does not match its schema
+var ErrSchemaViolation = errors.New("glog: schema violation")
+
+// RecordSchema returns the JSON Schema describing records written by
+// the JSON handler with the default field keys
+func RecordSchema() []byte {
+	return slices.Clone(recordSchema)
+}
+
+// AuditSchema returns the JSON Schema describing audit records
+func AuditSchema() []byte {
+	return slices.Clone(auditSchema)
+}
+
+var (
+	parsedRecordSchema = sync.OnceValues(func() (*jsonSchema, error) { return parseSchema(recordSchema) })
+	parsedAuditSchema  = sync.OnceValues(func() (*jsonSchema, error) { return parseSchema(auditSchema) })
+)
+
+// ValidateRecord checks a JSON encoded log record against RecordSchema
+func ValidateRecord(data []byte) error {
+	schema, err := parsedRecordSchema()
+	if err != nil {
+		return err
+	}
+	return validateAgainst(schema, data)
+}
+
+// ValidateAuditRecord checks a JSON encoded audit record against AuditSchema
+func ValidateAuditRecord(data []byte) error {
+	schema, err := parsedAuditSchema()
+	if err != nil {
+		return err
+	}
+	return validateAgainst(schema, data)
+}
+
+// jsonSchema is the subset of JSON Schema used by the published schemas
+type jsonSchema struct {
+	Type       schemaType             `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Enum       []any                  `json:"enum"`
+	MinLength  *int                   `json:"minLength"`
+	MaxLength  *int                   `json:"maxLength"`
+}
+
+// schemaType accepts both the string and the array form of "type"
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = []string{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func parseSchema(data []byte) (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("glog: invalid schema: %w", err)
+	}
+	return &schema, nil
+}
+
+func validateAgainst(schema *jsonSchema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: invalid JSON: %v", ErrSchemaViolation, err)
+	}
+
+	return schema.validate("$", doc)
+}
+
+func (s *jsonSchema) validate(path string, v any) error {
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return matchesType(t, v) }) {
+		return fmt.Errorf("%w: %s: expected %v, got %s", ErrSchemaViolation, path, []string(s.Type), jsonTypeName(v))
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		return fmt.Errorf("%w: %s: %v not in %v", ErrSchemaViolation, path, v, s.Enum)
+	}
+
+	if str, ok := v.(string); ok {
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%w: %s: shorter than %d", ErrSchemaViolation, path, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%w: %s: longer than %d", ErrSchemaViolation, path, *s.MaxLength)
+		}
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for _, key := range s.Required {
+		if _, ok := obj[key]; !ok {
+			return fmt.Errorf("%w: %s: missing required %q", ErrSchemaViolation, path, key)
+		}
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if val, ok := obj[key]; ok {
+			if err := s.Properties[key].validate(path+"."+key, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesType(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return false
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,93 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Query record attribute keys
+const (
+	QueryKey       = "query"
+	ExplainHintKey = "explain_hint"
+)
+
+// SlowQueryPolicy decides how database queries are logged, so the
+// slow query behavior of every database adapter is configured once
+//
+//	policy := glog.SlowQueryPolicy{Threshold: 200 * time.Millisecond, ExplainHint: true}
+//	logger.LogQuery(ctx, policy, query, elapsed, err)
+type SlowQueryPolicy struct {
+	// Threshold is the duration above which queries are logged at Warn
+	Threshold time.Duration
+	// ErrorThreshold escalates slower queries to Error, zero disables it
+	ErrorThreshold time.Duration
+	// Level logs queries under Threshold, they are not logged when it
+	// is below the logger level, e.g. LevelTrace
+	Level slog.Level
+	// ExplainHint adds an EXPLAIN statement for slow queries. It is not
+	// EXPLAIN ANALYZE, which would run the query again when pasted.
+	ExplainHint bool
+}
+
+// DefaultSlowQueryPolicy warns about queries slower than 200ms and
+// logs the others at Trace
+var DefaultSlowQueryPolicy = SlowQueryPolicy{
+	Threshold: 200 * time.Millisecond,
+	Level:     LevelTrace,
+}
+
+// QueryLevel returns the level of a query that took elapsed and failed
+// with err, failed queries are always logged at Error
+func (p SlowQueryPolicy) QueryLevel(elapsed time.Duration, err error) slog.Level {
+	switch {
+	case err != nil:
+		return slog.LevelError
+	case p.ErrorThreshold > 0 && elapsed > p.ErrorThreshold:
+		return slog.LevelError
+	case p.Threshold > 0 && elapsed > p.Threshold:
+		return slog.LevelWarn
+	}
+	return p.Level
+}
+
+// IsSlow reports whether elapsed is above the policy Threshold
+func (p SlowQueryPolicy) IsSlow(elapsed time.Duration) bool {
+	return p.Threshold > 0 && elapsed > p.Threshold
+}
+
+// LogQuery logs query with its duration as policy decides, args are
+// added to the record
+func (c *BaseLogger) LogQuery(ctx context.Context, policy SlowQueryPolicy, query string, elapsed time.Duration, err error, args ...any) {
+	level := policy.QueryLevel(elapsed, err)
+	logger := c.logger.Load()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	msg := "query"
+	attrs := argsToAttrs(args)
+	attrs = append(attrs,
+		slog.String(QueryKey, query),
+		slog.Duration("duration", elapsed),
+	)
+	if policy.IsSlow(elapsed) {
+		msg = "slow query"
+		attrs = append(attrs, slog.Duration("threshold", policy.Threshold))
+		if policy.ExplainHint {
+			attrs = append(attrs, slog.String(ExplainHintKey, "EXPLAIN "+query))
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, Err(err))
+	}
+
+	var pcs [1]uintptr
+	// skip runtime.Callers and LogQuery
+	runtime.Callers(2+c.callerSkip, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	c.handle(ctx, logger, r)
+}
@@ -0,0 +1,70 @@
+package glog
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// SourceFormat controls how the source attribute is rendered
+type SourceFormat int
+
+const (
+	// SourceFull keeps the absolute path reported by slog
+	SourceFull SourceFormat = iota
+	// SourcePackage renders the parent directory and file, e.g. glog/logger.go:42
+	SourcePackage
+	// SourceFile renders only the file name, e.g. logger.go:42
+	SourceFile
+)
+
+// formatSource rewrites src based on format after removing trimPrefix
+// from the file path. SourceFull returns the source as a structured
+// value, other formats return a "path:line" string. If withFunc is
+// set the result is always a "pkg.Func (path:line)" string
+func formatSource(src *slog.Source, format SourceFormat, trimPrefix string, withFunc bool) slog.Value {
+	file := src.File
+	if trimPrefix != "" {
+		file = strings.TrimPrefix(strings.TrimPrefix(file, trimPrefix), "/")
+	}
+
+	switch format {
+	case SourcePackage:
+		dir := filepath.Base(filepath.Dir(file))
+		if dir == "." || dir == "/" {
+			file = filepath.Base(file)
+		} else {
+			file = dir + "/" + filepath.Base(file)
+		}
+	case SourceFile:
+		file = filepath.Base(file)
+	default:
+		if withFunc {
+			break
+		}
+		if trimPrefix == "" {
+			return slog.AnyValue(src)
+		}
+		return slog.AnyValue(&slog.Source{
+			Function: src.Function,
+			File:     file,
+			Line:     src.Line,
+		})
+	}
+
+	if withFunc && src.Function != "" {
+		return slog.StringValue(fmt.Sprintf("%s (%s:%d)", shortFuncName(src.Function), file, src.Line))
+	}
+
+	return slog.StringValue(fmt.Sprintf("%s:%d", file, src.Line))
+}
+
+// shortFuncName strips the import path from a fully qualified
+// function name, e.g. github.com/a/b/pkg.(*T).Fn becomes pkg.(*T).Fn
+func shortFuncName(fn string) string {
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		return fn[i+1:]
+	}
+	return fn
+}
@@ -0,0 +1,105 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Keys of the trace correlation attributes
+const (
+	TraceIDKey  = "trace_id"
+	SpanIDKey   = "span_id"
+	SpanNameKey = "span_name"
+)
+
+// SpanInfo identifies the active trace span of a context
+type SpanInfo struct {
+	TraceID    string
+	SpanID     string
+	Name       string
+	Sampled    bool
+	TraceState string
+}
+
+// Valid reports whether the span has a trace ID
+func (s SpanInfo) Valid() bool {
+	return s.TraceID != ""
+}
+
+// SpanExtractor returns the active span of ctx. Use WithSpanExtractor
+// to read spans from a tracing SDK, e.g. for OpenTelemetry:
+//
+//	glog.WithSpanExtractor(func(ctx context.Context) (glog.SpanInfo, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return glog.SpanInfo{
+//			TraceID: sc.TraceID().String(),
+//			SpanID:  sc.SpanID().String(),
+//			Sampled: sc.IsSampled(),
+//		}, sc.IsValid()
+//	})
+type SpanExtractor func(ctx context.Context) (SpanInfo, bool)
+
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span, for services
+// that propagate trace context without a tracing SDK
+func ContextWithSpan(ctx context.Context, span SpanInfo) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span stored with ContextWithSpan
+func SpanFromContext(ctx context.Context) (SpanInfo, bool) {
+	if ctx == nil {
+		return SpanInfo{}, false
+	}
+	span, ok := ctx.Value(spanKey{}).(SpanInfo)
+	return span, ok && span.Valid()
+}
+
+// FromSpan returns a copy of the logger bound to ctx and tagged with
+// the trace and span IDs and the name of its active span. Without an
+// active span it returns the copy bound to ctx.
+func (c *BaseLogger) FromSpan(ctx context.Context) *BaseLogger {
+	out := c.clone()
+	out.ctx = ctx
+
+	span, ok := c.extractSpan()(ctx)
+	if !ok {
+		return out
+	}
+
+	var attrs []any
+	if !hasCtxAttr(ctx, TraceIDKey) {
+		// ContextWithTraceparent already tags records logged with ctx
+		attrs = spanAttrs(span)
+	}
+	if span.Name != "" {
+		attrs = append(attrs, slog.String(SpanNameKey, span.Name))
+	}
+	return out.With(attrs...)
+}
+
+// extractSpan returns the span extractor of the logger
+func (c *BaseLogger) extractSpan() SpanExtractor {
+	if c.spanExtractor == nil {
+		return SpanFromContext
+	}
+	return c.spanExtractor
+}
+
+func hasCtxAttr(ctx context.Context, key string) bool {
+	for _, a := range CtxAttrs(ctx) {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func spanAttrs(span SpanInfo) []any {
+	attrs := []any{slog.String(TraceIDKey, span.TraceID)}
+	if span.SpanID != "" {
+		attrs = append(attrs, slog.String(SpanIDKey, span.SpanID))
+	}
+	return attrs
+}
@@ -0,0 +1,30 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestFromSpan(t *testing.T) {
+	var out bytes.Buffer
+	root := NewLogger(WithOutput(&out))
+	l := root.With("request", "r1")
+
+	ctx := ContextWithSpan(context.Background(), SpanInfo{TraceID: "t1", SpanID: "s1", Name: "checkout"})
+	l.FromSpan(ctx).Info("paid")
+
+	var rec map[string]any
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{"request": "r1", TraceIDKey: "t1", SpanIDKey: "s1", SpanNameKey: "checkout"} {
+		if rec[k] != want {
+			t.Errorf("%s = %v, want %s", k, rec[k], want)
+		}
+	}
+	if names := root.LoggerNames(); len(names) != 0 {
+		t.Fatalf("FromSpan registered loggers %v", names)
+	}
+}
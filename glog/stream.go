@@ -0,0 +1,221 @@
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	streamBufferSize = 256
+	streamHeartbeat  = 15 * time.Second
+)
+
+// LogStream fans out JSON records to connected clients. Attach it to a
+// logger with WithStream and mount it on a mux to tail logs over
+// server sent events:
+//
+//	curl -N 'localhost:8080/debug/logs?level=warn&where=tenant=acme'
+//
+// Query parameters: level sets the minimum level, logger matches the
+// logger name, q matches a substring of the encoded record and where
+// adds a key=value filter, dotted keys address nested groups.
+// Slow clients drop records instead of blocking the logger.
+type LogStream struct {
+	mu          sync.RWMutex
+	subscribers map[*streamSubscriber]struct{}
+}
+
+type streamSubscriber struct {
+	filter  streamFilter
+	ch      chan []byte
+	mu      sync.Mutex
+	dropped int
+}
+
+type streamFilter struct {
+	level slog.Level
+	text  string
+	where map[string]string
+}
+
+func NewLogStream() *LogStream {
+	return &LogStream{
+		subscribers: map[*streamSubscriber]struct{}{},
+	}
+}
+
+// active reports whether any client is connected
+func (s *LogStream) active() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subscribers) > 0
+}
+
+// publish sends the encoded record line to the matching subscribers
+func (s *LogStream) publish(level slog.Level, line []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var fields map[string]any
+	for sub := range s.subscribers {
+		if level < sub.filter.level {
+			continue
+		}
+		if sub.filter.text != "" && !strings.Contains(string(line), sub.filter.text) {
+			continue
+		}
+		if len(sub.filter.where) > 0 {
+			if fields == nil {
+				fields = map[string]any{}
+				if err := json.Unmarshal(line, &fields); err != nil {
+					continue
+				}
+			}
+			if !matchFields(fields, sub.filter.where) {
+				continue
+			}
+		}
+
+		select {
+		case sub.ch <- line:
+		default:
+			sub.mu.Lock()
+			sub.dropped++
+			sub.mu.Unlock()
+		}
+	}
+}
+
+func (s *LogStream) subscribe(filter streamFilter) *streamSubscriber {
+	sub := &streamSubscriber{
+		filter: filter,
+		ch:     make(chan []byte, streamBufferSize),
+	}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *LogStream) unsubscribe(sub *streamSubscriber) {
+	s.mu.Lock()
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+}
+
+// ServeHTTP streams matching records as server sent events until
+// the client disconnects
+func (s *LogStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := parseStreamFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub := s.subscribe(filter)
+	defer s.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-sub.ch:
+			sub.mu.Lock()
+			dropped := sub.dropped
+			sub.dropped = 0
+			sub.mu.Unlock()
+
+			if dropped > 0 {
+				fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", dropped)
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", line); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func parseStreamFilter(r *http.Request) (streamFilter, error) {
+	query := r.URL.Query()
+	filter := streamFilter{
+		level: LevelTrace,
+		text:  query.Get("q"),
+		where: map[string]string{},
+	}
+
+	if name := query.Get("level"); name != "" {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return filter, err
+		}
+		filter.level = level
+	}
+
+	if name := query.Get("logger"); name != "" {
+		filter.where[LoggerKey] = name
+	}
+
+	for _, expr := range query["where"] {
+		key, val, ok := strings.Cut(expr, "=")
+		if !ok || key == "" {
+			return filter, fmt.Errorf("invalid where filter %q, expected key=value", expr)
+		}
+		filter.where[key] = val
+	}
+
+	return filter, nil
+}
+
+func matchFields(fields map[string]any, where map[string]string) bool {
+	for key, want := range where {
+		v, ok := lookupField(fields, key)
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupField resolves a dotted key against decoded JSON fields
+func lookupField(fields map[string]any, key string) (any, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+
+	group, ok := fields[head].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupField(group, rest)
+}
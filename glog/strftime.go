@@ -0,0 +1,66 @@
+package glog
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// strftime formats t using a subset of strftime directives:
+// %Y %y %m %d %H %M %S %j and %% for a literal percent sign
+func strftime(format string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 == len(format) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch format[i] {
+		case 'Y':
+			sb.WriteString(strconv.Itoa(t.Year()))
+		case 'y':
+			sb.WriteString(t.Format("06"))
+		case 'm':
+			sb.WriteString(t.Format("01"))
+		case 'd':
+			sb.WriteString(t.Format("02"))
+		case 'H':
+			sb.WriteString(t.Format("15"))
+		case 'M':
+			sb.WriteString(t.Format("04"))
+		case 'S':
+			sb.WriteString(t.Format("05"))
+		case 'j':
+			sb.WriteString(t.Format("002"))
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(format[i])
+		}
+	}
+	return sb.String()
+}
+
+// strftimeGlob turns a strftime template into a glob pattern
+func strftimeGlob(format string) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 == len(format) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		i++
+		if format[i] == '%' {
+			sb.WriteByte('%')
+		} else {
+			sb.WriteByte('*')
+		}
+	}
+	return sb.String()
+}
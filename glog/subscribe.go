@@ -0,0 +1,152 @@
+package glog
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const subscriptionBufferSize = 256
+
+// Record is a resolved log record delivered to subscribers. Attrs holds
+// the logger attributes followed by the call attributes, with groups
+// applied.
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Logger  string
+	Source  *slog.Source
+	Attrs   []slog.Attr
+}
+
+// Attr returns the value of key, dotted keys address nested groups
+func (r Record) Attr(key string) (slog.Value, bool) {
+	return lookupAttr(r.Attrs, key)
+}
+
+func lookupAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value.Resolve(), true
+		}
+	}
+
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return slog.Value{}, false
+	}
+
+	for _, a := range attrs {
+		if a.Key == head && a.Value.Kind() == slog.KindGroup {
+			return lookupAttr(a.Value.Group(), rest)
+		}
+	}
+	return slog.Value{}, false
+}
+
+// RecordFilter selects the records delivered to a subscriber
+type RecordFilter func(r Record) bool
+
+// Subscribe returns a channel that receives the records logged by this
+// logger or, on the root logger, by any logger in the tree. A nil filter
+// selects every record. Records are only delivered if they pass the
+// logger level and focus checks, and are dropped when the subscriber
+// falls behind. Call cancel to stop the subscription and close the channel.
+func (c *BaseLogger) Subscribe(filter RecordFilter) (<-chan Record, func()) {
+	sub := &subscription{
+		filter: filter,
+		ch:     make(chan Record, subscriptionBufferSize),
+	}
+	if c.getRoot() != c {
+		sub.logger = c.name
+	}
+
+	c.subs.add(sub)
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() { c.subs.remove(sub) })
+	}
+}
+
+type subscription struct {
+	logger string
+	filter RecordFilter
+	ch     chan Record
+
+	// fn receives records synchronously instead of ch
+	fn func(Record)
+}
+
+// subscriptions is shared by all loggers in a tree
+type subscriptions struct {
+	mu    sync.RWMutex
+	subs  map[*subscription]struct{}
+	count atomic.Int32
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{subs: map[*subscription]struct{}{}}
+}
+
+func (s *subscriptions) add(sub *subscription) {
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.count.Add(1)
+	s.mu.Unlock()
+}
+
+func (s *subscriptions) remove(sub *subscription) {
+	s.mu.Lock()
+	delete(s.subs, sub)
+	s.count.Add(-1)
+	if sub.ch != nil {
+		close(sub.ch)
+	}
+	s.mu.Unlock()
+}
+
+// observe calls fn for every record of the tree that passes filter,
+// on the logging goroutine
+func (s *subscriptions) observe(filter RecordFilter, fn func(Record)) {
+	s.add(&subscription{filter: filter, fn: fn})
+}
+
+func (s *subscriptions) active() bool {
+	return s.count.Load() > 0
+}
+
+func (s *subscriptions) publish(rec Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subs {
+		if sub.logger != "" && sub.logger != rec.Logger {
+			continue
+		}
+		if sub.filter != nil && !sub.filter(rec) {
+			continue
+		}
+		if sub.fn != nil {
+			sub.fn(rec)
+			continue
+		}
+		select {
+		case sub.ch <- rec:
+		default:
+		}
+	}
+}
+
+func recordSourceFromPC(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	f, _ := frames.Next()
+	return &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+}
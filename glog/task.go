@@ -0,0 +1,77 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	TaskOutcomeSuccess = "success"
+	TaskOutcomeFailure = "failure"
+)
+
+// Task is a logger scoped to a unit of work. Records logged through it
+// carry the "task" attribute, and Success or Fail emit the end record.
+type Task struct {
+	*BaseLogger
+	name  string
+	start time.Time
+	ended atomic.Bool
+}
+
+// Begin logs the start of task name and returns a logger scoped to it
+//
+//	task := logger.Begin(ctx, "migrate db", "version", 42)
+//	if err := migrate(); err != nil {
+//		task.Fail(err)
+//		return
+//	}
+//	task.Success()
+func (c *BaseLogger) Begin(ctx context.Context, name string, args ...any) *Task {
+	scoped := c.clone()
+	scoped.ctx = ctx
+	scoped = scoped.With(append([]any{slog.String("task", name)}, args...)...)
+
+	t := &Task{
+		BaseLogger: scoped,
+		name:       name,
+		start:      time.Now(),
+	}
+
+	scoped.log(ctx, 0, slog.LevelInfo, name+" started")
+
+	return t
+}
+
+// Success logs the task completion at Info, only the first call to
+// Success or Fail emits a record
+func (t *Task) Success(args ...any) {
+	if !t.ended.CompareAndSwap(false, true) {
+		return
+	}
+
+	dargs := append(args[:len(args):len(args)],
+		slog.String("outcome", TaskOutcomeSuccess),
+		slog.Duration("duration", time.Since(t.start)),
+	)
+	t.log(t.ctx, 0, slog.LevelInfo, t.name+" succeeded", dargs...)
+}
+
+// Fail logs the task failure at Error including err details when err
+// is not nil, only the first call to Success or Fail emits a record
+func (t *Task) Fail(err error, args ...any) {
+	if !t.ended.CompareAndSwap(false, true) {
+		return
+	}
+
+	dargs := append(args[:len(args):len(args)],
+		slog.String("outcome", TaskOutcomeFailure),
+		slog.Duration("duration", time.Since(t.start)),
+	)
+	if err != nil {
+		dargs = append(dargs, err)
+	}
+	t.logError(t.ctx, slog.LevelError, t.name+" failed", dargs...)
+}
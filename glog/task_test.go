@@ -0,0 +1,34 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTaskFail(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"error", errors.New("disk full"), `"error":"disk full"`},
+		{"nil error", nil, `"outcome":"failure"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(WithOutput(&buf), WithBadKeyMode(BadKeyPanic))
+
+			l.Begin(context.Background(), "migrate").Fail(tt.err)
+
+			out := buf.String()
+			if !strings.Contains(out, tt.want) || strings.Contains(out, "BADKEY") {
+				t.Fatalf("failure record = %s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package glog
+
+import (
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// TenantKey is the attribute added to records of tenant loggers
+const TenantKey = "tenant"
+
+// tenantConfig holds the per tenant overrides shared by a logger tree
+type tenantConfig struct {
+	sinks  map[string]io.Writer
+	levels map[string]string
+}
+
+// clone returns a copy that can be changed without affecting t
+func (t *tenantConfig) clone() *tenantConfig {
+	if t == nil {
+		return nil
+	}
+	return &tenantConfig{
+		sinks:  maps.Clone(t.sinks),
+		levels: maps.Clone(t.levels),
+	}
+}
+
+func (c *BaseLogger) tenantConfig() *tenantConfig {
+	if c.tenants == nil {
+		c.tenants = &tenantConfig{
+			sinks:  map[string]io.Writer{},
+			levels: map[string]string{},
+		}
+	}
+	return c.tenants
+}
+
+// ForTenant returns a logger that adds a "tenant" attribute with id to
+// every record. Output goes to the sink registered for id with
+// WithTenantSink and the level registered with WithTenantLevel, falling
+// back to the configuration of c. The attributes and context of c are
+// kept.
+//
+// Tenant loggers are cached by the logger of the tree c belongs to for
+// its lifetime, so ids must come from a small known set, e.g. the
+// configured tenants, and not from request input.
+//
+//	log := logger.GetLogger("api").ForTenant(tenantID)
+func (c *BaseLogger) ForTenant(id string) *BaseLogger {
+	root := c.getRoot()
+	root.mu.Lock()
+	// copies made by With and WithContext share the tenants of their
+	// tree logger, the tree is what Flush and Close walk
+	owner := root
+	if l, ok := root.loggers[c.name]; ok && c.name != "" {
+		owner = l
+	}
+	tenant := owner.tenant(id)
+	root.mu.Unlock()
+
+	if c == owner {
+		return tenant
+	}
+
+	out := tenant.clone()
+	out.ctx = c.ctx
+	out.callerSkip = c.callerSkip
+	if len(c.withArgs) > 0 {
+		out.withArgs = c.withArgs
+		out.logger.Store(tenant.logger.Load().With(c.withArgs...))
+	}
+	return out
+}
+
+// tenant returns the cached tenant logger of c for id, the caller must
+// hold the root lock
+func (c *BaseLogger) tenant(id string) *BaseLogger {
+	if out, ok := c.tenantLoggers[id]; ok {
+		return out
+	}
+
+	out := c.derive(c.name)
+	out.defaultAttrs = append(slices.Clone(c.defaultAttrs), slog.String(TenantKey, id))
+
+	// tenants share the buffers and counters of their logger
+	out.recent = c.recent
+	out.seq = c.seq
+
+	if c.tenants != nil {
+		if w, ok := c.tenants.sinks[id]; ok {
+			out.stdout = w
+			out.stderr = nil
+			out.base = nil
+		}
+		if level, ok := c.tenants.levels[id]; ok {
+			out.levelVar.Set(getLevel(level))
+		}
+	}
+
+	out.configureLogger()
+
+	if c.tenantLoggers == nil {
+		c.tenantLoggers = map[string]*BaseLogger{}
+	}
+	c.tenantLoggers[id] = out
+
+	return out
+}
@@ -0,0 +1,153 @@
+package glog
+
+import "github.com/fatih/color"
+
+// Theme holds the palette used by ColorConsoleHandler.
+// A nil color renders the text unstyled.
+type Theme struct {
+	Trace *color.Color
+	Debug *color.Color
+	Info  *color.Color
+	Warn  *color.Color
+	Error *color.Color
+	Fatal *color.Color
+
+	// Badge colors are used for levels when badges are enabled
+	TraceBadge *color.Color
+	DebugBadge *color.Color
+	InfoBadge  *color.Color
+	WarnBadge  *color.Color
+	ErrorBadge *color.Color
+	FatalBadge *color.Color
+
+	Timestamp  *color.Color
+	Message    *color.Color
+	Key        *color.Color
+	ErrorKey   *color.Color
+	LoggerName *color.Color
+	Source     *color.Color
+	Stack      *color.Color
+}
+
+// DarkTheme is the default palette, tuned for dark terminal backgrounds
+func DarkTheme() Theme {
+	return Theme{
+		Trace:      color.New(color.FgHiBlack),
+		Debug:      color.New(color.FgMagenta),
+		Info:       color.New(color.FgBlue),
+		Warn:       color.New(color.FgYellow),
+		Error:      color.New(color.FgRed, color.Bold),
+		Fatal:      color.New(color.FgRed, color.Bold),
+		TraceBadge: color.New(color.BgHiBlack, color.FgWhite),
+		DebugBadge: color.New(color.BgMagenta, color.FgWhite),
+		InfoBadge:  color.New(color.BgBlue, color.FgWhite),
+		WarnBadge:  color.New(color.BgYellow, color.FgBlack),
+		ErrorBadge: color.New(color.BgRed, color.FgWhite, color.Bold),
+		FatalBadge: color.New(color.BgRed, color.FgWhite, color.Bold),
+		Timestamp:  color.New(color.FgHiBlack),
+		Message:    color.New(color.FgWhite),
+		Key:        color.New(color.FgHiYellow),
+		ErrorKey:   color.New(color.FgHiRed),
+		LoggerName: color.New(color.FgGreen, color.Bold),
+		Source:     color.New(color.FgHiBlack),
+		Stack:      color.New(color.FgHiBlack),
+	}
+}
+
+// LightTheme is a palette for light terminal backgrounds
+func LightTheme() Theme {
+	return Theme{
+		Trace:      color.New(color.FgHiBlack),
+		Debug:      color.New(color.FgMagenta),
+		Info:       color.New(color.FgBlue),
+		Warn:       color.New(color.FgYellow, color.Bold),
+		Error:      color.New(color.FgRed, color.Bold),
+		Fatal:      color.New(color.FgRed, color.Bold),
+		TraceBadge: color.New(color.BgWhite, color.FgBlack),
+		DebugBadge: color.New(color.BgMagenta, color.FgWhite),
+		InfoBadge:  color.New(color.BgBlue, color.FgWhite),
+		WarnBadge:  color.New(color.BgYellow, color.FgBlack),
+		ErrorBadge: color.New(color.BgRed, color.FgWhite, color.Bold),
+		FatalBadge: color.New(color.BgRed, color.FgWhite, color.Bold),
+		Timestamp:  color.New(color.FgBlack),
+		Message:    color.New(color.FgBlack),
+		Key:        color.New(color.FgCyan),
+		ErrorKey:   color.New(color.FgRed),
+		LoggerName: color.New(color.FgGreen, color.Bold),
+		Source:     color.New(color.FgBlack),
+		Stack:      color.New(color.FgBlack),
+	}
+}
+
+// HighContrastTheme uses bold, bright colors and background
+// badges for warnings and errors
+func HighContrastTheme() Theme {
+	return Theme{
+		Trace:      color.New(color.FgHiWhite),
+		Debug:      color.New(color.FgHiMagenta, color.Bold),
+		Info:       color.New(color.FgHiCyan, color.Bold),
+		Warn:       color.New(color.BgHiYellow, color.FgBlack, color.Bold),
+		Error:      color.New(color.BgHiRed, color.FgHiWhite, color.Bold),
+		Fatal:      color.New(color.BgHiRed, color.FgHiWhite, color.Bold),
+		TraceBadge: color.New(color.BgHiWhite, color.FgBlack),
+		DebugBadge: color.New(color.BgHiMagenta, color.FgBlack, color.Bold),
+		InfoBadge:  color.New(color.BgHiCyan, color.FgBlack, color.Bold),
+		WarnBadge:  color.New(color.BgHiYellow, color.FgBlack, color.Bold),
+		ErrorBadge: color.New(color.BgHiRed, color.FgHiWhite, color.Bold),
+		FatalBadge: color.New(color.BgHiRed, color.FgHiWhite, color.Bold),
+		Timestamp:  color.New(color.FgHiWhite),
+		Message:    color.New(color.FgHiWhite, color.Bold),
+		Key:        color.New(color.FgHiYellow, color.Bold),
+		ErrorKey:   color.New(color.FgHiRed, color.Bold),
+		LoggerName: color.New(color.FgHiGreen, color.Bold),
+		Source:     color.New(color.FgHiWhite),
+		Stack:      color.New(color.FgHiWhite),
+	}
+}
+
+// paint renders s with c, or returns s unchanged if c is nil
+func paint(c *color.Color, s string) string {
+	if c == nil {
+		return s
+	}
+	return c.Sprint(s)
+}
+
+// clone returns a copy of the theme where every color is force
+// enabled or disabled, so the result does not depend on color.NoColor
+func (t Theme) clone(enabled bool) Theme {
+	set := func(c *color.Color) *color.Color {
+		if c == nil {
+			return nil
+		}
+		cc := *c
+		if enabled {
+			cc.EnableColor()
+		} else {
+			cc.DisableColor()
+		}
+		return &cc
+	}
+
+	return Theme{
+		Trace:      set(t.Trace),
+		Debug:      set(t.Debug),
+		Info:       set(t.Info),
+		Warn:       set(t.Warn),
+		Error:      set(t.Error),
+		Fatal:      set(t.Fatal),
+		TraceBadge: set(t.TraceBadge),
+		DebugBadge: set(t.DebugBadge),
+		InfoBadge:  set(t.InfoBadge),
+		WarnBadge:  set(t.WarnBadge),
+		ErrorBadge: set(t.ErrorBadge),
+		FatalBadge: set(t.FatalBadge),
+		Timestamp:  set(t.Timestamp),
+		Message:    set(t.Message),
+		Key:        set(t.Key),
+		ErrorKey:   set(t.ErrorKey),
+		LoggerName: set(t.LoggerName),
+		Source:     set(t.Source),
+		Stack:      set(t.Stack),
+	}
+}
@@ -0,0 +1,66 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Timer logs msg at Debug and returns a func that logs its completion
+// at Info with the elapsed time as "duration"
+//
+//	done := logger.Timer("load config")
+//	defer done()
+func (c *BaseLogger) Timer(msg string, args ...any) func() {
+	return c.timer(slog.LevelInfo, msg, args...)
+}
+
+// TimerAt is like Timer but logs the completion record at level
+func (c *BaseLogger) TimerAt(level slog.Level, msg string, args ...any) func() {
+	return c.timer(level, msg, args...)
+}
+
+func (c *BaseLogger) timer(level slog.Level, msg string, args ...any) func() {
+	start := time.Now()
+	c.log(c.ctx, 1, slog.LevelDebug, msg+" started", args...)
+
+	return func() {
+		dargs := append(args[:len(args):len(args)], slog.Duration("duration", time.Since(start)))
+		c.log(c.ctx, 0, level, msg+" completed", dargs...)
+	}
+}
+
+// WarnIfSlow returns a func that logs msg at Warn with the elapsed time
+// as "duration" when more than threshold passed since WarnIfSlow was
+// called, and does nothing otherwise. The record points at the caller
+// of WarnIfSlow.
+//
+//	defer logger.WarnIfSlow(ctx, "render report", 2*time.Second)()
+func (c *BaseLogger) WarnIfSlow(ctx context.Context, msg string, threshold time.Duration, args ...any) func() {
+	start := time.Now()
+
+	var pcs [1]uintptr
+	// skip runtime.Callers and WarnIfSlow
+	runtime.Callers(2+c.callerSkip, pcs[:])
+
+	return func() {
+		elapsed := time.Since(start)
+		if elapsed <= threshold {
+			return
+		}
+
+		logger := c.logger.Load()
+		if !logger.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, msg, pcs[0])
+		r.AddAttrs(argsToAttrs(args)...)
+		r.AddAttrs(
+			slog.Duration("duration", elapsed),
+			slog.Duration("threshold", threshold),
+		)
+		c.handle(ctx, logger, r)
+	}
+}
@@ -0,0 +1,37 @@
+package glog
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	TimeFormatRFC3339      = time.RFC3339
+	TimeFormatRFC3339Nano  = time.RFC3339Nano
+	TimeFormatEpochSeconds = "epoch"
+	TimeFormatEpochMillis  = "epoch_millis"
+)
+
+// formatTime renders v using layout in loc. An empty layout keeps the
+// handler's native time encoding, a nil loc keeps the record's zone.
+func formatTime(v slog.Value, layout string, loc *time.Location) slog.Value {
+	if v.Kind() != slog.KindTime {
+		return v
+	}
+
+	t := v.Time()
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	switch layout {
+	case "":
+		return slog.TimeValue(t)
+	case TimeFormatEpochSeconds:
+		return slog.Int64Value(t.Unix())
+	case TimeFormatEpochMillis:
+		return slog.Int64Value(t.UnixMilli())
+	default:
+		return slog.StringValue(t.Format(layout))
+	}
+}
@@ -0,0 +1,123 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// W3C trace context header names
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// maxTracestateMembers is the limit on tracestate list members
+const maxTracestateMembers = 32
+
+// ParseTraceparent parses W3C traceparent and tracestate header values,
+// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". An
+// invalid tracestate is dropped as the specification requires, only an
+// invalid traceparent is an error.
+func ParseTraceparent(traceparent, tracestate string) (SpanInfo, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return SpanInfo{}, fmt.Errorf("glog: invalid traceparent %q", traceparent)
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isLowerHex(version, 2) || version == "ff":
+		return SpanInfo{}, fmt.Errorf("glog: invalid traceparent version %q", version)
+	case version == "00" && len(parts) != 4:
+		return SpanInfo{}, fmt.Errorf("glog: invalid traceparent %q", traceparent)
+	case !isLowerHex(traceID, 32) || isZeros(traceID):
+		return SpanInfo{}, fmt.Errorf("glog: invalid trace id %q", traceID)
+	case !isLowerHex(spanID, 16) || isZeros(spanID):
+		return SpanInfo{}, fmt.Errorf("glog: invalid parent id %q", spanID)
+	case !isLowerHex(flags, 2):
+		return SpanInfo{}, fmt.Errorf("glog: invalid trace flags %q", flags)
+	}
+
+	bits, _ := strconv.ParseUint(flags, 16, 8)
+	span := SpanInfo{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: bits&1 == 1,
+	}
+
+	if state, err := parseTracestate(tracestate); err == nil {
+		span.TraceState = state
+	}
+
+	return span, nil
+}
+
+// ContextWithTraceparent parses the headers and returns a copy of ctx
+// carrying the span, records logged with it include its trace and
+// span IDs, see AppendCtx
+func ContextWithTraceparent(ctx context.Context, traceparent, tracestate string) (context.Context, error) {
+	span, err := ParseTraceparent(traceparent, tracestate)
+	if err != nil {
+		return ctx, err
+	}
+	ctx = ContextWithSpan(ctx, span)
+	return AppendCtx(ctx, slog.String(TraceIDKey, span.TraceID), slog.String(SpanIDKey, span.SpanID)), nil
+}
+
+// TraceContextFromHeader is ContextWithTraceparent for the trace context
+// headers of a request, ctx is returned unchanged when they are missing
+// or invalid
+//
+//	ctx := glog.TraceContextFromHeader(r.Context(), r.Header)
+//	logger.InfoContext(ctx, "handled")
+func TraceContextFromHeader(ctx context.Context, header http.Header) context.Context {
+	traceparent := header.Get(TraceparentHeader)
+	if traceparent == "" {
+		return ctx
+	}
+	out, _ := ContextWithTraceparent(ctx, traceparent, strings.Join(header.Values(TracestateHeader), ","))
+	return out
+}
+
+// parseTracestate validates the tracestate list and returns it with
+// empty members removed
+func parseTracestate(tracestate string) (string, error) {
+	var members []string
+	for _, member := range strings.Split(tracestate, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || key == "" || value == "" || strings.ContainsAny(key, " \t") {
+			return "", errors.New("glog: invalid tracestate member")
+		}
+		members = append(members, member)
+	}
+	if len(members) > maxTracestateMembers {
+		return "", errors.New("glog: too many tracestate members")
+	}
+	return strings.Join(members, ","), nil
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeros(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,85 @@
+package glog
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		tracestate  string
+		want        SpanInfo
+		wantErr     bool
+	}{
+		{
+			name:        "sampled",
+			traceparent: "00-" + traceID + "-" + spanID + "-01",
+			want:        SpanInfo{TraceID: traceID, SpanID: spanID, Sampled: true},
+		},
+		{
+			name:        "not sampled",
+			traceparent: "00-" + traceID + "-" + spanID + "-00",
+			want:        SpanInfo{TraceID: traceID, SpanID: spanID},
+		},
+		{
+			name:        "tracestate",
+			traceparent: "00-" + traceID + "-" + spanID + "-01",
+			tracestate:  "rojo=00f067aa0ba902b7, ,congo=t61rcWkgMzE",
+			want:        SpanInfo{TraceID: traceID, SpanID: spanID, Sampled: true, TraceState: "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE"},
+		},
+		{
+			name:        "invalid tracestate is dropped",
+			traceparent: "00-" + traceID + "-" + spanID + "-01",
+			tracestate:  "rojo",
+			want:        SpanInfo{TraceID: traceID, SpanID: spanID, Sampled: true},
+		},
+		{
+			name:        "future version with extra fields",
+			traceparent: "cc-" + traceID + "-" + spanID + "-01-extra",
+			want:        SpanInfo{TraceID: traceID, SpanID: spanID, Sampled: true},
+		},
+		{name: "empty", traceparent: "", wantErr: true},
+		{name: "version ff", traceparent: "ff-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "version 00 with extra fields", traceparent: "00-" + traceID + "-" + spanID + "-01-extra", wantErr: true},
+		{name: "upper case", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", wantErr: true},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-" + spanID + "-01", wantErr: true},
+		{name: "zero parent id", traceparent: "00-" + traceID + "-0000000000000000-01", wantErr: true},
+		{name: "short trace id", traceparent: "00-4bf92f35-" + spanID + "-01", wantErr: true},
+		{name: "bad flags", traceparent: "00-" + traceID + "-" + spanID + "-zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTraceparent(tt.traceparent, tt.tracestate)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ParseTraceparent() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseTraceparent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceContextFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := TraceContextFromHeader(context.Background(), header)
+	if span, ok := SpanFromContext(ctx); !ok || span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("span = %+v, %v", span, ok)
+	}
+
+	header.Set(TraceparentHeader, "garbage")
+	if ctx := TraceContextFromHeader(context.Background(), header); ctx != context.Background() {
+		t.Fatal("an invalid traceparent changed the context")
+	}
+}
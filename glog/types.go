@@ -28,22 +28,28 @@ type LoggerProvider interface {
 }
 
 const (
-	LevelTrace = slog.Level(-8)
-	LevelFatal = slog.Level(20)
+	LevelTrace  = slog.Level(-8)
+	LevelDPanic = slog.Level(12)
+	LevelPanic  = slog.Level(16)
+	LevelFatal  = slog.Level(20)
 )
 
 const (
-	Trace = "TRACE"
-	Debug = "DEBUG"
-	Info  = "INFO"
-	Warn  = "WARN"
-	Error = "ERROR"
-	Fatal = "FATAL"
+	Trace  = "TRACE"
+	Debug  = "DEBUG"
+	Info   = "INFO"
+	Warn   = "WARN"
+	Error  = "ERROR"
+	DPanic = "DPANIC"
+	Panic  = "PANIC"
+	Fatal  = "FATAL"
 )
 
 var CustomLevels = map[slog.Leveler]string{
-	LevelTrace: Trace,
-	LevelFatal: Fatal,
+	LevelTrace:  Trace,
+	LevelDPanic: DPanic,
+	LevelPanic:  Panic,
+	LevelFatal:  Fatal,
 }
 
 const (
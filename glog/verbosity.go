@@ -0,0 +1,68 @@
+package glog
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+)
+
+type ctxLevelKey struct{}
+
+// ContextWithLevel returns a copy of ctx that lowers the level of the
+// records logged with it to level, e.g. to log a single request at
+// Debug. It never raises the logger level.
+//
+//	ctx = glog.ContextWithLevel(ctx, slog.LevelDebug)
+//	logger.DebugContext(ctx, "cache miss")
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	if prev, ok := LevelFromContext(ctx); ok && prev <= level {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxLevelKey{}, level)
+}
+
+// LevelFromContext returns the level set with ContextWithLevel
+func LevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(ctxLevelKey{}).(slog.Level)
+	return level, ok
+}
+
+// RolloutSampled reports whether id falls in the given fraction of
+// IDs. The decision is a hash of id, so every service handling the
+// same request ID makes the same one.
+func RolloutSampled(id string, fraction float64) bool {
+	if fraction <= 0 || id == "" {
+		return false
+	}
+	if fraction >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	// the top 53 bits as a float in [0, 1)
+	return float64(mix64(h.Sum64())>>11)/(1<<53) < fraction
+}
+
+// mix64 spreads the bits of FNV hashes of short, similar IDs such as
+// sequence numbers, it is the murmur3 finalizer
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// ContextWithRollout lowers the level of ctx to Debug for the given
+// fraction of request IDs, see RolloutSampled, so production keeps a
+// statistically useful share of debug records
+func ContextWithRollout(ctx context.Context, requestID string, fraction float64) context.Context {
+	if !RolloutSampled(requestID, fraction) {
+		return ctx
+	}
+	return ContextWithLevel(ctx, slog.LevelDebug)
+}
@@ -0,0 +1,117 @@
+package glog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Defaults of NewBufferedWriter
+const (
+	DefaultBufferSize     = 64 << 10
+	DefaultBufferInterval = time.Second
+)
+
+// BufferedWriter batches writes to the wrapped writer. Buffered data is
+// written when it would exceed the buffer size or interval after the
+// first buffered write, whichever comes first, so records are never
+// older than interval when they reach the output. It is safe for
+// concurrent use.
+type BufferedWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	buf      []byte
+	size     int
+	interval time.Duration
+	timer    *time.Timer
+	closed   bool
+}
+
+// NewBufferedWriter wraps w with a buffer of size bytes flushed at
+// least every interval, zero values use the defaults
+func NewBufferedWriter(w io.Writer, size int, interval time.Duration) *BufferedWriter {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	if interval <= 0 {
+		interval = DefaultBufferInterval
+	}
+	return &BufferedWriter{
+		w:        w,
+		buf:      make([]byte, 0, size),
+		size:     size,
+		interval: interval,
+	}
+}
+
+func (b *BufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if len(b.buf)+len(p) > b.size {
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	// records larger than the buffer bypass it
+	if len(p) >= b.size {
+		return b.w.Write(p)
+	}
+
+	if len(b.buf) == 0 {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.interval, b.flushTimer)
+		} else {
+			b.timer.Reset(b.interval)
+		}
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// Flush writes the buffered data
+func (b *BufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush()
+}
+
+// Close flushes the buffer and stops the flush timer, the wrapped
+// writer is left open
+func (b *BufferedWriter) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+
+	return b.flush()
+}
+
+func (b *BufferedWriter) flushTimer() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_ = b.flush()
+}
+
+func (b *BufferedWriter) flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	_, err := b.w.Write(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
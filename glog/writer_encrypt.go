@@ -0,0 +1,147 @@
+package glog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownKey is returned when decrypting a frame sealed with a key
+// that was not provided
+var ErrUnknownKey = errors.New("glog: unknown encryption key")
+
+// EncryptWriter seals every Write with AES-GCM and writes it as a
+// single "<key id>.<base64 nonce+ciphertext>" line, so each record can
+// be decrypted on its own and keys can be rotated at any point.
+type EncryptWriter struct {
+	mu    sync.Mutex
+	out   io.Writer
+	keyID string
+	aead  cipher.AEAD
+}
+
+// NewEncryptWriter returns a writer that encrypts to out using key,
+// which must be 16, 24 or 32 bytes long. keyID is stored with every
+// frame to select the key when decrypting.
+func NewEncryptWriter(out io.Writer, keyID string, key []byte) (*EncryptWriter, error) {
+	w := &EncryptWriter{out: out}
+	if err := w.Rotate(keyID, key); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Rotate switches the key used for subsequent writes
+func (w *EncryptWriter) Rotate(keyID string, key []byte) error {
+	if keyID == "" || strings.ContainsAny(keyID, ".\n") {
+		return fmt.Errorf("glog: invalid key id %q", keyID)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.keyID = keyID
+	w.aead = aead
+	return nil
+}
+
+func (w *EncryptWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	nonce := make([]byte, w.aead.NonceSize(), w.aead.NonceSize()+len(p)+w.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, err
+	}
+
+	sealed := w.aead.Seal(nonce, nonce, p, []byte(w.keyID))
+
+	frame := make([]byte, 0, len(w.keyID)+1+base64.StdEncoding.EncodedLen(len(sealed))+1)
+	frame = append(frame, w.keyID...)
+	frame = append(frame, '.')
+	frame = base64.StdEncoding.AppendEncode(frame, sealed)
+	frame = append(frame, '\n')
+
+	if _, err := w.out.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// DecryptLog reads frames written by EncryptWriter from src and writes
+// the plaintext to dst. keys maps key ids to keys.
+func DecryptLog(dst io.Writer, src io.Reader, keys map[string][]byte) error {
+	aeads := map[string]cipher.AEAD{}
+
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		frame := bytes.TrimSpace(scanner.Bytes())
+		if len(frame) == 0 {
+			continue
+		}
+
+		keyID, payload, ok := bytes.Cut(frame, []byte("."))
+		if !ok {
+			return fmt.Errorf("glog: line %d: malformed frame", line)
+		}
+
+		aead, ok := aeads[string(keyID)]
+		if !ok {
+			key, found := keys[string(keyID)]
+			if !found {
+				return fmt.Errorf("%w: line %d: %s", ErrUnknownKey, line, keyID)
+			}
+			var err error
+			if aead, err = newAEAD(key); err != nil {
+				return err
+			}
+			aeads[string(keyID)] = aead
+		}
+
+		sealed, err := base64.StdEncoding.AppendDecode(nil, payload)
+		if err != nil {
+			return fmt.Errorf("glog: line %d: %w", line, err)
+		}
+
+		if len(sealed) < aead.NonceSize() {
+			return fmt.Errorf("glog: line %d: frame too short", line)
+		}
+
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plain, err := aead.Open(nil, nonce, ciphertext, keyID)
+		if err != nil {
+			return fmt.Errorf("glog: line %d: %w", line, err)
+		}
+
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("glog: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
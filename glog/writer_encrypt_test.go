@@ -0,0 +1,86 @@
+package glog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncryptWriter(t *testing.T) {
+	key1 := bytes.Repeat([]byte{1}, 32)
+	key2 := bytes.Repeat([]byte{2}, 16)
+
+	var sealed bytes.Buffer
+	w, err := NewEncryptWriter(&sealed, "k1", key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("first record\n"))
+	if err := w.Rotate("k2", key2); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("second record\n"))
+
+	if strings.Contains(sealed.String(), "record") {
+		t.Fatal("plaintext in the encrypted output")
+	}
+
+	keys := map[string][]byte{"k1": key1, "k2": key2}
+	tampered := []byte(sealed.String())
+	tampered[len("k1.")+5] ^= 'A' ^ 'B'
+
+	tests := []struct {
+		name    string
+		input   string
+		keys    map[string][]byte
+		want    string
+		wantErr error
+	}{
+		{name: "both keys", input: sealed.String(), keys: keys, want: "first record\nsecond record\n"},
+		{name: "missing key", input: sealed.String(), keys: map[string][]byte{"k1": key1}, wantErr: ErrUnknownKey},
+		{name: "wrong key", input: sealed.String(), keys: map[string][]byte{"k1": key2, "k2": key2}},
+		{name: "tampered", input: string(tampered), keys: keys},
+		{name: "malformed", input: "no frame here\n", keys: keys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plain bytes.Buffer
+			err := DecryptLog(&plain, strings.NewReader(tt.input), tt.keys)
+			if tt.want != "" {
+				if err != nil || plain.String() != tt.want {
+					t.Fatalf("DecryptLog() = %q, %v, want %q", plain.String(), err, tt.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("DecryptLog() succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecryptLog() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptWriterInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		keyID string
+		key   []byte
+	}{
+		{"empty id", "", make([]byte, 32)},
+		{"id with dot", "a.b", make([]byte, 32)},
+		{"id with newline", "a\nb", make([]byte, 32)},
+		{"short key", "k", make([]byte, 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEncryptWriter(&bytes.Buffer{}, tt.keyID, tt.key); err == nil {
+				t.Fatal("NewEncryptWriter() accepted an invalid key")
+			}
+		})
+	}
+}
@@ -0,0 +1,546 @@
+package glog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// renameFile moves files to backups, tests replace it to make
+// rotation fail
+var renameFile = os.Rename
+
+// FileOption configures a FileWriter
+type FileOption func(*FileWriter)
+
+// WithFileMaxSize rotates the file before a write would make it larger
+// than size bytes, zero disables size based rotation
+func WithFileMaxSize(size int64) FileOption {
+	return func(fw *FileWriter) {
+		fw.maxSize = size
+	}
+}
+
+// WithFileMaxAge removes backups older than age, zero keeps them
+func WithFileMaxAge(age time.Duration) FileOption {
+	return func(fw *FileWriter) {
+		fw.maxAge = age
+	}
+}
+
+// WithFileMaxBackups keeps at most n backups, zero keeps them all
+func WithFileMaxBackups(n int) FileOption {
+	return func(fw *FileWriter) {
+		fw.maxBackups = n
+	}
+}
+
+// WithFileCompress gzips backups after rotation
+func WithFileCompress(enabled bool) FileOption {
+	return func(fw *FileWriter) {
+		fw.compress = enabled
+	}
+}
+
+// WithFileRotateEvery rotates the file when the clock crosses a period
+// boundary, e.g. time.Hour or 24*time.Hour for hourly or daily rotation.
+// Periods of whole days start at local midnight.
+func WithFileRotateEvery(period time.Duration) FileOption {
+	return func(fw *FileWriter) {
+		fw.period = period
+	}
+}
+
+// WithFileSymlink maintains a symlink at link pointing to the current file
+func WithFileSymlink(link string) FileOption {
+	return func(fw *FileWriter) {
+		fw.symlink = link
+	}
+}
+
+// WithFilePerm sets the mode used to create log files, defaults to 0644
+func WithFilePerm(perm os.FileMode) FileOption {
+	return func(fw *FileWriter) {
+		fw.perm = perm
+	}
+}
+
+// WithFileSync fsyncs the file after every write so records survive
+// a power failure once Write returns, at the cost of throughput. Each
+// write is one record, or one batch when the writer is wrapped in a
+// BufferedWriter. The directory is synced when files are created or
+// rotated, so the new names survive too.
+func WithFileSync() FileOption {
+	return func(fw *FileWriter) {
+		fw.sync = true
+	}
+}
+
+// FileWriter appends to a log file, rotating it into timestamped
+// backups based on size. Backups are named after the file with the
+// rotation time inserted before the extension, e.g.
+// app-2006-01-02T15-04-05.000.log. It is safe for concurrent use.
+//
+// If the path contains strftime directives, e.g. app-%Y%m%d.log, it is
+// used as a template: the writer opens a new file named after the
+// current time at every period boundary instead of renaming backups.
+type FileWriter struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+
+	template   string
+	period     time.Duration
+	nextRotate time.Time
+	symlink    string
+
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	perm       os.FileMode
+	sync       bool
+
+	millMu sync.Mutex
+	millWg sync.WaitGroup
+}
+
+// NewFileWriter opens path for appending, creating it and its
+// directory if needed
+func NewFileWriter(path string, opts ...FileOption) (*FileWriter, error) {
+	w := &FileWriter{
+		path: path,
+		perm: 0o644,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	now := time.Now()
+	if strings.Contains(path, "%") {
+		w.template = path
+		w.path = strftime(path, now)
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	w.nextRotate = w.periodEnd(now)
+
+	return w, nil
+}
+
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.period > 0 {
+		if now := time.Now(); !now.Before(w.nextRotate) {
+			if err := w.rotatePeriod(now); err != nil && w.file == nil {
+				return 0, err
+			}
+		}
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		// when the file was reopened the record still goes to it and
+		// rotation is tried again on the next write
+		if err := w.rotate(); err != nil && w.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err == nil && w.sync {
+		err = w.file.Sync()
+	}
+	return n, err
+}
+
+// Name returns the path of the current file
+func (w *FileWriter) Name() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// Sync commits the current file to stable storage
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.file.Sync()
+}
+
+// Rotate moves the current file to a backup and starts a new one
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file at the same path. Use it after an
+// external tool such as logrotate has moved the file away.
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+
+	return w.open()
+}
+
+// ReopenOnSignal calls Reopen every time one of sigs is received until
+// the returned stop func is called
+func (w *FileWriter) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = w.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for pending backup compression
+// and cleanup to finish
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.millWg.Wait()
+	return err
+}
+
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.perm)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	// commits the new file and, after rotate, the rename of the old one
+	if w.sync {
+		if err := syncDir(filepath.Dir(w.path)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	w.file = f
+	w.size = info.Size()
+
+	if w.symlink != "" {
+		w.updateSymlink()
+	}
+	return nil
+}
+
+// updateSymlink points the symlink at the current file, relative
+// when both live in the same directory
+func (w *FileWriter) updateSymlink() {
+	target := w.path
+	if filepath.Dir(target) == filepath.Dir(w.symlink) {
+		target = filepath.Base(target)
+	}
+
+	tmp := w.symlink + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, w.symlink); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// periodEnd returns the next period boundary after t
+func (w *FileWriter) periodEnd(t time.Time) time.Time {
+	if w.period <= 0 {
+		return time.Time{}
+	}
+
+	day := 24 * time.Hour
+	if w.period%day == 0 {
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return midnight.AddDate(0, 0, int(w.period/day))
+	}
+
+	return t.Truncate(w.period).Add(w.period)
+}
+
+// rotatePeriod starts the file for the period containing now
+func (w *FileWriter) rotatePeriod(now time.Time) error {
+	w.nextRotate = w.periodEnd(now)
+
+	if w.template == "" {
+		return w.rotate()
+	}
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+
+	prev := w.path
+	w.path = strftime(w.template, now)
+	if err := w.open(); err != nil {
+		// keep writing to the previous file rather than failing
+		// every write until the next period
+		w.path = prev
+		return errors.Join(err, w.open())
+	}
+
+	w.millWg.Add(1)
+	go w.mill()
+
+	return nil
+}
+
+func (w *FileWriter) rotate() error {
+	if w.file != nil {
+		err := w.file.Close()
+		w.file = nil
+		if err != nil {
+			return errors.Join(err, w.open())
+		}
+	}
+
+	if _, err := os.Stat(w.path); err == nil {
+		if err := renameFile(w.path, w.backupName(time.Now())); err != nil {
+			// reopen the current file so writes continue
+			return errors.Join(err, w.open())
+		}
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.millWg.Add(1)
+	go w.mill()
+
+	return nil
+}
+
+// backupName returns a backup path for t that does not exist yet
+func (w *FileWriter) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	base := filepath.Join(dir, prefix+t.Format(backupTimeFormat))
+
+	name := base + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+	return name
+}
+
+// nameParts splits the path into directory, backup prefix and extension
+func (w *FileWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.path)
+	base := filepath.Base(w.path)
+	ext = filepath.Ext(base)
+	prefix = strings.TrimSuffix(base, ext) + "-"
+	return dir, prefix, ext
+}
+
+type logBackup struct {
+	path string
+	t    time.Time
+}
+
+// backups lists rotated files for this writer, newest first
+func (w *FileWriter) backups() ([]logBackup, error) {
+	if w.template != "" {
+		return w.templateBackups()
+	}
+
+	dir, prefix, ext := w.nameParts()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []logBackup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		stamp = strings.TrimSuffix(stamp, ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+
+		t, err := time.ParseInLocation(backupTimeFormat, stamp[:len(backupTimeFormat)], time.Local)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, logBackup{path: filepath.Join(dir, name), t: t})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].t.After(out[j].t) })
+	return out, nil
+}
+
+// templateBackups lists files matching the path template other than
+// the current file, newest first by modification time
+func (w *FileWriter) templateBackups() ([]logBackup, error) {
+	w.mu.Lock()
+	current := w.path
+	w.mu.Unlock()
+
+	matches, err := filepath.Glob(strftimeGlob(w.template) + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []logBackup
+	for _, m := range matches {
+		if m == current || m == w.symlink {
+			continue
+		}
+		info, err := os.Lstat(m)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		out = append(out, logBackup{path: m, t: info.ModTime()})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].t.After(out[j].t) })
+	return out, nil
+}
+
+// mill compresses and prunes backups, runs are serialized
+func (w *FileWriter) mill() {
+	defer w.millWg.Done()
+
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+
+	var keep []logBackup
+	cutoff := time.Now().Add(-w.maxAge)
+	for i, b := range backups {
+		expired := w.maxAge > 0 && b.t.Before(cutoff)
+		excess := w.maxBackups > 0 && i >= w.maxBackups
+		if expired || excess {
+			os.Remove(b.path)
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if !w.compress {
+		return
+	}
+
+	for _, b := range keep {
+		if strings.HasSuffix(b.path, ".gz") {
+			continue
+		}
+		_ = compressFile(b.path)
+	}
+}
+
+// compressFile gzips path into path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	err = errors.Join(err, gz.Close(), dst.Close())
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
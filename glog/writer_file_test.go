@@ -0,0 +1,103 @@
+package glog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestFileWriterRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []FileOption
+		writes      int
+		wantBackups int
+		wantSuffix  string
+	}{
+		{"size", []FileOption{WithFileMaxSize(10)}, 4, 3, ".log"},
+		{"max backups", []FileOption{WithFileMaxSize(10), WithFileMaxBackups(1)}, 4, 1, ".log"},
+		{"compressed", []FileOption{WithFileMaxSize(10), WithFileCompress(true)}, 3, 2, ".log.gz"},
+		{"synced", []FileOption{WithFileMaxSize(10), WithFileSync()}, 3, 2, ".log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := NewFileWriter(filepath.Join(dir, "app.log"), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.writes; i++ {
+				if _, err := w.Write([]byte("0123456789")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			var backups int
+			for _, name := range readDir(t, dir) {
+				if name == "app.log" {
+					continue
+				}
+				if !strings.HasPrefix(name, "app-") || !strings.HasSuffix(name, tt.wantSuffix) {
+					t.Errorf("unexpected file %s", name)
+				}
+				backups++
+			}
+			if backups != tt.wantBackups {
+				t.Fatalf("got %d backups, want %d: %v", backups, tt.wantBackups, readDir(t, dir))
+			}
+		})
+	}
+}
+
+func TestFileWriterFailedRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path, WithFileMaxSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	renameFile = func(string, string) error { return errors.New("rename failed") }
+	defer func() { renameFile = os.Rename }()
+
+	if err := w.Rotate(); err == nil {
+		t.Fatal("Rotate() succeeded with a failing rename")
+	}
+	for _, line := range []string{"first\n", "second after a failed size rotation\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() after a failed rotate: %v", err)
+		}
+	}
+
+	renameFile = os.Rename
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "third\n" {
+		t.Fatalf("current file = %q, want rotation to resume", data)
+	}
+}
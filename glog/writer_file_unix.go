@@ -0,0 +1,25 @@
+//go:build !windows
+
+package glog
+
+import (
+	"os"
+	"syscall"
+)
+
+// ReopenOnSIGUSR1 reopens the file whenever the process receives
+// SIGUSR1, the usual postrotate hook for logrotate
+func (w *FileWriter) ReopenOnSIGUSR1() (stop func()) {
+	return w.ReopenOnSignal(syscall.SIGUSR1)
+}
+
+// syncDir commits the entries of dir, so files created or renamed in
+// it survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package glog
+
+// syncDir does nothing, directories cannot be synced on windows
+func syncDir(string) error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package glog
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// GzipWriter compresses everything written to it into a single gzip
+// stream. Pending data is flushed every interval so consumers tailing
+// the output are not starved while the logger is idle.
+type GzipWriter struct {
+	mu    sync.Mutex
+	gz    *gzip.Writer
+	dirty bool
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewGzipWriter returns a writer compressing to out at level, see the
+// compress/gzip constants. An interval of zero disables periodic
+// flushing. Close must be called to write the gzip footer.
+func NewGzipWriter(out io.Writer, level int, interval time.Duration) (*GzipWriter, error) {
+	gz, err := gzip.NewWriterLevel(out, level)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &GzipWriter{
+		gz:   gz,
+		done: make(chan struct{}),
+	}
+
+	if interval > 0 {
+		w.wg.Add(1)
+		go w.flushLoop(interval)
+	}
+
+	return w, nil
+}
+
+func (w *GzipWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.dirty = true
+	return w.gz.Write(p)
+}
+
+// Flush writes any pending compressed data to the underlying writer
+func (w *GzipWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.dirty {
+		return nil
+	}
+
+	w.dirty = false
+	return w.gz.Flush()
+}
+
+// Close stops periodic flushing and finishes the gzip stream.
+// It does not close the underlying writer.
+func (w *GzipWriter) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+		close(w.done)
+	}
+
+	w.wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.gz.Close()
+}
+
+func (w *GzipWriter) flushLoop(interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = w.Flush()
+		case <-w.done:
+			return
+		}
+	}
+}
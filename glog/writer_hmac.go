@@ -0,0 +1,117 @@
+package glog
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sync"
+)
+
+// HMACKey is the attribute holding a record signature
+const HMACKey = "hmac"
+
+// ErrInvalidSignature is returned when a record signature does not match
+var ErrInvalidSignature = errors.New("glog: invalid record signature")
+
+// HMACWriter appends an HMAC-SHA256 signature to every line written
+// through it. JSON lines get a trailing "hmac" member, other lines a
+// trailing hmac=<hex> pair. Partial writes are buffered until a newline.
+type HMACWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	key []byte
+	buf []byte
+}
+
+func NewHMACWriter(out io.Writer, key []byte) *HMACWriter {
+	return &HMACWriter{
+		out: out,
+		key: key,
+	}
+}
+
+func (w *HMACWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		line := signLine(w.buf[:i], w.key)
+		w.buf = w.buf[i+1:]
+
+		if _, err := w.out.Write(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// signLine returns line with its signature appended and a newline
+func signLine(line, key []byte) []byte {
+	sig := lineSignature(line, key)
+
+	out := make([]byte, 0, len(line)+len(sig)+12)
+	if isJSONObject(line) {
+		out = append(out, line[:len(line)-1]...)
+		out = append(out, `,"`+HMACKey+`":"`...)
+		out = append(out, sig...)
+		out = append(out, `"}`...)
+	} else {
+		out = append(out, line...)
+		out = append(out, " "+HMACKey+"="...)
+		out = append(out, sig...)
+	}
+	return append(out, '\n')
+}
+
+// VerifyHMACLine checks the signature of a single line written by an
+// HMACWriter, the trailing newline is optional
+func VerifyHMACLine(line, key []byte) error {
+	line = bytes.TrimRight(line, "\n")
+
+	var unsigned, sig []byte
+	if isJSONObject(line) {
+		marker := []byte(`,"` + HMACKey + `":"`)
+		i := bytes.LastIndex(line, marker)
+		if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return ErrInvalidSignature
+		}
+		sig = line[i+len(marker) : len(line)-2]
+		unsigned = append(append([]byte{}, line[:i]...), '}')
+	} else {
+		marker := []byte(" " + HMACKey + "=")
+		i := bytes.LastIndex(line, marker)
+		if i < 0 {
+			return ErrInvalidSignature
+		}
+		sig = line[i+len(marker):]
+		unsigned = line[:i]
+	}
+
+	if !hmac.Equal(sig, lineSignature(unsigned, key)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func lineSignature(line, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(line)
+	sum := mac.Sum(nil)
+
+	sig := make([]byte, hex.EncodedLen(len(sum)))
+	hex.Encode(sig, sum)
+	return sig
+}
+
+func isJSONObject(line []byte) bool {
+	return len(line) >= 2 && line[0] == '{' && line[len(line)-1] == '}'
+}
@@ -0,0 +1,28 @@
+package glog
+
+import (
+	"errors"
+	"io"
+)
+
+// RotatingWriter is an output that rotates its file on demand. It is
+// satisfied by *lumberjack.Logger from gopkg.in/natefinch/lumberjack.v2
+// and by *FileWriter.
+type RotatingWriter interface {
+	io.WriteCloser
+	Rotate() error
+}
+
+// Rotate rotates the outputs of the root logger that support it, e.g.
+// from a SIGHUP handler
+func (c *BaseLogger) Rotate() error {
+	var errs []error
+	for _, closer := range c.getRoot().closers {
+		if w, ok := closer.(RotatingWriter); ok {
+			if err := w.Rotate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
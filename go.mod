@@ -2,10 +2,10 @@ module github.com/goliatone/go-logger
 
 go 1.23.4
 
-require github.com/fatih/color v1.18.0
-
 require (
-	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	github.com/fatih/color v1.18.0
+	github.com/mattn/go-colorable v0.1.13
+	github.com/mattn/go-isatty v0.0.20
+	golang.org/x/sys v0.25.0
+	golang.org/x/term v0.24.0
 )